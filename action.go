@@ -9,6 +9,7 @@ const (
 	EDIT
 	DELETE
 	LIST
+	FMT
 )
 
 var actionString = []string{
@@ -18,6 +19,7 @@ var actionString = []string{
 	"edit",
 	"delete",
 	"list",
+	"fmt",
 }
 
 var actionStringToAction = func() map[string]Action {
@@ -1,9 +1,13 @@
 package main
 
 import (
+	"errors"
+	"flag"
 	"fmt"
 	"os"
 	"path/filepath"
+
+	"github.com/thansetan/git-sw/pkg/gitconfig"
 )
 
 type Command struct {
@@ -23,7 +27,11 @@ var commands = map[Action]Command{
 			if err != nil {
 				return err
 			}
-			err = saveProfile(profilePath, profile.Name, profile.Config)
+			content, err := profile.Config.MarshalText()
+			if err != nil {
+				return err
+			}
+			err = saveProfile(profilePath, profile.Name, content)
 			if err != nil {
 				return err
 			}
@@ -72,10 +80,15 @@ var commands = map[Action]Command{
 		Func: func() error {
 			var (
 				selected Profile
+				saved    bool
 				err      error
 			)
 			if isGlobal {
-				err = openTextEditor(filepath.Join(userHomeDir, ".gitconfig"))
+				path, err := gitConfigPath(true)
+				if err != nil {
+					return err
+				}
+				saved, err = editConfig(path)
 				if err != nil {
 					return err
 				}
@@ -89,15 +102,85 @@ var commands = map[Action]Command{
 			if selected.Name == "default" {
 				return ErrEditDefaultConfig
 			}
-			err = openTextEditor(filepath.Join(saveDirPath, selected.DirName, ".gitconfig"))
+			saved, err = editConfig(filepath.Join(saveDirPath, selected.DirName, ".gitconfig"))
 			if err != nil {
 				return err
 			}
 		successMsg:
+			if !saved {
+				return nil
+			}
 			fmt.Println(successMessage(selected.Name, EDIT))
 			return nil
 		},
 	},
+	FMT: {
+		Description: "Format config files, or an existing profile if none is given.",
+		Func: func() error {
+			fs := flag.NewFlagSet(FMT.String(), flag.ContinueOnError)
+			check := fs.Bool("check", false, "Don't write the files, list the ones that aren't formatted and fail if there's any.")
+			sortKeys := fs.Bool("sort", false, "Sort the variables of every section by name.")
+			err := fs.Parse(flag.Args()[1:])
+			if errors.Is(err, flag.ErrHelp) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			opts := gitconfig.FormatOptions{SortKeys: *sortKeys}
+
+			if fs.NArg() > 0 {
+				var unformatted bool
+				for _, path := range fs.Args() {
+					formatted, err := formatConfig(path, opts, *check)
+					if err != nil {
+						return err
+					}
+					if !formatted {
+						fmt.Println(path)
+						unformatted = true
+					}
+				}
+				if unformatted {
+					return ErrNotFormatted
+				}
+				return nil
+			}
+
+			var (
+				selected  Profile
+				path      string
+				formatted bool
+			)
+			if isGlobal {
+				path, err = gitConfigPath(true)
+				if err != nil {
+					return err
+				}
+				selected.Name = ".gitconfig"
+				goto format
+			}
+			selected, err = displayProfileSelector(profiles)
+			if err != nil {
+				return err
+			}
+			if selected.Name == defaultConfigName {
+				return ErrFmtDefaultConfig
+			}
+			path = filepath.Join(saveDirPath, selected.DirName, ".gitconfig")
+		format:
+			formatted, err = formatConfig(path, opts, *check)
+			if err != nil {
+				return err
+			}
+			if !formatted {
+				fmt.Println(path)
+				return ErrNotFormatted
+			}
+			fmt.Println(successMessage(selected.Name, FMT))
+			return nil
+		},
+	},
 	DELETE: {
 		Description: "Delete an existing profile.",
 		Func: func() error {
@@ -136,7 +219,11 @@ var commands = map[Action]Command{
 				return err
 			}
 			if deleteGlobal {
-				err = os.Remove(filepath.Join(userHomeDir, ".gitconfig"))
+				path, err := gitConfigPath(true)
+				if err != nil {
+					return err
+				}
+				err = os.Remove(path)
 				if err != nil {
 					return err
 				}
@@ -16,4 +16,6 @@ var (
 	ErrDeleteDefaultConfig = fmt.Errorf("use '%s -g delete' to delete default config", os.Args[0])
 	ErrInvalidPublicKeyExt = errors.New("invalid public key file extension")
 	ErrNotGitDirectory     = errors.New("not in a git directory")
+	ErrFmtDefaultConfig    = fmt.Errorf("use '%s -g fmt' to format default config", os.Args[0])
+	ErrNotFormatted        = errors.New("config files aren't formatted")
 )
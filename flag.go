@@ -14,6 +14,7 @@ var (
 		USE:    {},
 		EDIT:   {},
 		DELETE: {},
+		FMT:    {},
 	}
 )
 
@@ -31,6 +32,6 @@ func parseFlag() {
 		fmt.Fprint(flag.CommandLine.Output(), sb.String())
 		flag.PrintDefaults()
 	}
-	flag.BoolVar(&isGlobal, "g", false, "Run the command globally (can only be used with the 'use', 'edit', and 'delete' commands).")
+	flag.BoolVar(&isGlobal, "g", false, "Run the command globally (can only be used with the 'use', 'edit', 'delete', and 'fmt' commands).")
 	flag.Parse()
 }
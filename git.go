@@ -1,8 +1,14 @@
 package main
 
 import (
+	"errors"
 	"fmt"
+	"os"
 	"os/exec"
+	"regexp"
+	"strings"
+
+	"github.com/thansetan/git-sw/pkg/gitconfig"
 )
 
 type GPGFormat string
@@ -24,47 +30,136 @@ func isGitDirectory() bool {
 	return cmd.ProcessState.ExitCode() == 0
 }
 
+// gitConfigPath returns the path of the global config file or of the config file of the current repository.
+func gitConfigPath(isGlobal bool) (string, error) {
+	if isGlobal {
+		return gitconfig.GlobalConfigPath()
+	}
+	gitOutput, err := exec.Command("git", "rev-parse", "--git-path", "config").Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(gitOutput)), nil
+}
+
+// loadConfig parses the config file at path. A *gitconfig.ParseError is returned
+// for files git may still accept, use gitConfigFile() for those.
+func loadConfig(path string) (*gitconfig.GitConfig, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return gitconfig.New(), nil
+		}
+		return nil, err
+	}
+	return gitconfig.Parse(content)
+}
+
+// gitConfigFile runs "git config --file path" with args. It returns
+// gitconfig.ErrKeyNotFound if git reports that the key doesn't exist.
+func gitConfigFile(path string, args ...string) ([]byte, error) {
+	cmd := exec.Command("git", append([]string{"config", "--file", path}, args...)...)
+	out, err := cmd.Output()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		switch exitErr.ExitCode() {
+		case 1, 5: // the key or the value to unset wasn't found
+			return nil, gitconfig.ErrKeyNotFound
+		}
+	}
+	return out, err
+}
+
 func unsetConfig(pattern string) error {
-	var cmd *exec.Cmd
-	if isGitDirectory() {
-		cmd = exec.Command("git", "config", "--unset", "include.path", pattern)
-	} else {
-		cmd = exec.Command("git", "config", "--global", "--unset", "include.path", pattern)
-	}
-	gitOutput, err := cmd.CombinedOutput()
-	if err != nil && cmd.ProcessState.ExitCode() != 5 { // try to unset an option that does not exist will give exit 5
-		fmt.Printf("git: %s", string(gitOutput))
+	path, err := gitConfigPath(!isGitDirectory())
+	if err != nil {
+		return err
+	}
+	config, err := loadConfig(path)
+	var parseErr *gitconfig.ParseError
+	if errors.As(err, &parseErr) {
+		_, err = gitConfigFile(path, "--unset-all", "include.path", pattern)
+		if errors.Is(err, gitconfig.ErrKeyNotFound) {
+			return nil
+		}
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	err = config.UnsetAll("include.path", pattern)
+	if err != nil {
+		if errors.Is(err, gitconfig.ErrKeyNotFound) {
+			return nil
+		}
 		return err
 	}
-	return nil
+	return config.Save(path)
 }
 
 func applyConfig(configPath string, isGlobal bool) error {
-	var cmd *exec.Cmd
-	if isGlobal {
-		cmd = exec.Command("git", "config", "--global", "--replace-all", "include.path", configPath, fmt.Sprintf("%s.*gitconfig$", saveDirName))
-	} else {
-		cmd = exec.Command("git", "config", "--replace-all", "include.path", configPath, fmt.Sprintf("%s.*gitconfig$", saveDirName))
+	path, err := gitConfigPath(isGlobal)
+	if err != nil {
+		return err
+	}
+	pattern := fmt.Sprintf("%s.*gitconfig$", saveDirName)
+	config, err := loadConfig(path)
+	var parseErr *gitconfig.ParseError
+	if errors.As(err, &parseErr) {
+		_, err = gitConfigFile(path, "--replace-all", "include.path", configPath, pattern)
+		return err
 	}
-	gitOutput, err := cmd.CombinedOutput()
 	if err != nil {
-		fmt.Printf("git: %s", string(gitOutput))
 		return err
 	}
-	return nil
+	err = config.ReplaceAll("include.path", gitconfig.EncodeValue(configPath), pattern)
+	if err != nil {
+		return err
+	}
+	return config.Save(path)
+}
+
+// includePaths returns the include.path values of the config file at path.
+func includePaths(path string) ([]string, error) {
+	config, err := loadConfig(path)
+	var parseErr *gitconfig.ParseError
+	if errors.As(err, &parseErr) {
+		out, err := gitConfigFile(path, "--null", "--get-all", "include.path")
+		if err != nil {
+			if errors.Is(err, gitconfig.ErrKeyNotFound) {
+				return nil, nil
+			}
+			return nil, err
+		}
+		return strings.Split(strings.TrimSuffix(string(out), "\x00"), "\x00"), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	for _, include := range config.Includes() {
+		if len(include.Condition) == 0 {
+			paths = append(paths, include.Path)
+		}
+	}
+	return paths, nil
 }
 
 func getCurrentConfig() (string, error) {
-	var cmd *exec.Cmd
-	if !isGlobal && isGitDirectory() {
-		cmd = exec.Command("git", "config", "--worktree", "--get", "include.path", fmt.Sprintf("%s.*gitconfig$", saveDirName))
-	} else {
-		cmd = exec.Command("git", "config", "--global", "--get", "include.path", fmt.Sprintf("%s.*gitconfig$", saveDirName))
-	}
-	gitOutput, err := cmd.CombinedOutput()
-	if err != nil && cmd.ProcessState.ExitCode() != 1 {
-		fmt.Printf("git: %s", string(gitOutput))
+	path, err := gitConfigPath(isGlobal || !isGitDirectory())
+	if err != nil {
 		return "", err
 	}
-	return string(gitOutput), nil
+	includes, err := includePaths(path)
+	if err != nil {
+		return "", err
+	}
+	pattern := regexp.MustCompile(fmt.Sprintf("%s.*gitconfig$", saveDirName))
+	for i := len(includes) - 1; i >= 0; i-- {
+		if pattern.MatchString(includes[i]) {
+			return includes[i], nil
+		}
+	}
+	return "", nil
 }
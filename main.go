@@ -34,7 +34,7 @@ func main() {
 		os.Exit(1)
 	}
 	if _, ok := allowedGlobal[action]; isGlobal && !ok {
-		errorAndExit(errors.New("flag -g can only be used with the 'use', 'edit', and 'delete' commands"))
+		errorAndExit(errors.New("flag -g can only be used with the 'use', 'edit', 'delete', and 'fmt' commands"))
 	}
 
 	userHomeDir, err = os.UserHomeDir()
@@ -0,0 +1,159 @@
+package gitconfig
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var (
+	colorNames = []string{"black", "red", "green", "yellow", "blue", "magenta", "cyan", "white"}
+	// color attributes with their SGR codes, and the codes that turn them off
+	colorAttributes = map[string]struct{ on, off int }{
+		"bold":    {1, 22},
+		"dim":     {2, 22},
+		"italic":  {3, 23},
+		"ul":      {4, 24},
+		"blink":   {5, 25},
+		"reverse": {7, 27},
+		"strike":  {9, 29},
+	}
+)
+
+// Color interprets the value as a color (e.g. "bold red ul", "#ff0000 black",
+// "reset 208") and returns the matching ANSI escape sequence, the same output as
+// "git config --get-color". An empty value results in an empty string.
+func (val Value) Color() (string, error) {
+	var (
+		fg, bg string
+		reset  bool
+		attrs  uint32
+	)
+
+	for _, word := range strings.Fields(val.String()) {
+		if strings.EqualFold(word, "reset") {
+			reset = true
+			continue
+		}
+
+		color, ok := parseColor(word)
+		if ok {
+			switch {
+			case len(fg) == 0:
+				fg = color
+			case len(bg) == 0:
+				bg = color
+			default:
+				return "", ErrInvalidColor
+			}
+			continue
+		}
+
+		code, ok := parseColorAttribute(word)
+		if !ok {
+			return "", ErrInvalidColor
+		}
+		attrs |= 1 << code
+	}
+
+	if !reset && attrs == 0 && isEmptyColor(fg) && isEmptyColor(bg) {
+		return "", nil
+	}
+
+	codes := make([]string, 0, 4)
+	if reset {
+		codes = append(codes, "")
+	}
+	for code := 0; attrs != 0; code++ {
+		if attrs&(1<<code) != 0 {
+			codes = append(codes, strconv.Itoa(code))
+			attrs &^= 1 << code
+		}
+	}
+	if !isEmptyColor(fg) {
+		codes = append(codes, colorCode(fg, false))
+	}
+	if !isEmptyColor(bg) {
+		codes = append(codes, colorCode(bg, true))
+	}
+
+	return "\033[" + strings.Join(codes, ";") + "m", nil
+}
+
+// parseColor normalizes a color word to either "normal", an ANSI color
+// code ("31"), a 256-color number ("5;208") or an RGB color ("2;255;0;0").
+func parseColor(word string) (string, bool) {
+	lower := strings.ToLower(word)
+	switch lower {
+	case "normal":
+		return "normal", true
+	case "default":
+		return "39", true
+	}
+
+	if len(word) == 7 && word[0] == '#' {
+		rgb, err := strconv.ParseUint(word[1:], 16, 32)
+		if err == nil {
+			return fmt.Sprintf("2;%d;%d;%d", rgb>>16, (rgb>>8)&0xff, rgb&0xff), true
+		}
+	}
+
+	offset := 30
+	if name, ok := strings.CutPrefix(lower, "bright"); ok {
+		offset, lower = 90, name
+	}
+	for i := range colorNames {
+		if lower == colorNames[i] {
+			return strconv.Itoa(offset + i), true
+		}
+	}
+
+	n, err := strconv.Atoi(word)
+	switch {
+	case err != nil || n < -1:
+		return "", false
+	case n == -1:
+		return "normal", true
+	case n < 8:
+		return strconv.Itoa(30 + n), true
+	case n < 16:
+		return strconv.Itoa(90 + n - 8), true
+	case n < 256:
+		return fmt.Sprintf("5;%d", n), true
+	}
+	return "", false
+}
+
+func parseColorAttribute(word string) (int, bool) {
+	name, negate := strings.CutPrefix(word, "no")
+	if negate {
+		name = strings.TrimPrefix(name, "-")
+	}
+	attr, ok := colorAttributes[name]
+	if !ok {
+		return 0, false
+	}
+	if negate {
+		return attr.off, true
+	}
+	return attr.on, true
+}
+
+func isEmptyColor(color string) bool {
+	return len(color) == 0 || color == "normal"
+}
+
+// colorCode returns the SGR parameters of a color parsed by parseColor.
+func colorCode(color string, background bool) string {
+	if strings.Contains(color, ";") {
+		if background {
+			return "48;" + color
+		}
+		return "38;" + color
+	}
+	if background {
+		n, _ := strconv.Atoi(color)
+		return strconv.Itoa(n + 10)
+	}
+	return color
+}
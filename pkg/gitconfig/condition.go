@@ -0,0 +1,145 @@
+package gitconfig
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// IncludeContext describes the repository that includeIf conditions are evaluated against.
+type IncludeContext struct {
+	// GitDir is the path of the repository's .git directory.
+	GitDir string
+	// Branch is the currently checked out branch, either as a short name ("main")
+	// or a full ref ("refs/heads/main"). Empty if HEAD is detached.
+	Branch string
+	// RemoteURLs are the URLs of every remote configured in the repository.
+	RemoteURLs []string
+}
+
+// Match reports whether condition, the subsection of an includeIf section, is
+// true for ctx. path is the config file containing the condition and is used to
+// resolve "gitdir:./" patterns. Like git, unknown conditions are always false.
+// Match can be used as LoadOptions.IncludeIf.
+func (ctx IncludeContext) Match(condition, path string) (bool, error) {
+	switch {
+	case strings.HasPrefix(condition, "gitdir:"):
+		return ctx.matchGitDir(condition[len("gitdir:"):], path, false)
+	case strings.HasPrefix(condition, "gitdir/i:"):
+		return ctx.matchGitDir(condition[len("gitdir/i:"):], path, true)
+	case strings.HasPrefix(condition, "onbranch:"):
+		return ctx.matchBranch(condition[len("onbranch:"):]), nil
+	case strings.HasPrefix(condition, "hasconfig:remote.*.url:"):
+		return ctx.matchRemoteURL(condition[len("hasconfig:remote.*.url:"):]), nil
+	}
+	return false, nil
+}
+
+func (ctx IncludeContext) matchGitDir(pattern, path string, icase bool) (bool, error) {
+	if len(ctx.GitDir) == 0 {
+		return false, nil
+	}
+
+	pattern, prefix, err := gitDirPattern(pattern, path)
+	if err != nil {
+		return false, err
+	}
+
+	flags := wmPathname
+	if icase {
+		flags |= wmCaseFold
+	}
+
+	// try the path with symlinks resolved first, then the plain absolute path,
+	// so "gitdir:~/work/" still matches if ~/work is a symlink
+	abs, err := filepath.Abs(ctx.GitDir)
+	if err != nil {
+		return false, err
+	}
+	candidates := []string{abs}
+	if real, err := filepath.EvalSymlinks(abs); err == nil && real != abs {
+		candidates = []string{real, abs}
+	}
+
+	for _, text := range candidates {
+		text = filepath.ToSlash(text)
+		if len(text) < prefix {
+			continue
+		}
+		// the prefix is compared literally so wildcard characters in the
+		// directory of the config file have no special meaning
+		if icase && !strings.EqualFold(pattern[:prefix], text[:prefix]) ||
+			!icase && pattern[:prefix] != text[:prefix] {
+			continue
+		}
+		if wildmatch(pattern[prefix:], text[prefix:], flags) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// gitDirPattern turns the pattern of a gitdir condition into a glob the way git
+// does, returning the length of the prefix that must be matched literally.
+func gitDirPattern(pattern, path string) (string, int, error) {
+	var prefix int
+
+	pattern, err := expandHome(pattern)
+	if err != nil {
+		return "", 0, err
+	}
+
+	switch {
+	case strings.HasPrefix(pattern, "./"):
+		if len(path) == 0 {
+			return "", 0, ErrRelativeCondition
+		}
+		dir, err := filepath.Abs(filepath.Dir(path))
+		if err != nil {
+			return "", 0, err
+		}
+		if real, err := filepath.EvalSymlinks(dir); err == nil {
+			dir = real
+		}
+		dir = filepath.ToSlash(dir)
+		pattern = dir + pattern[1:]
+		prefix = len(dir) + 1
+	case !filepath.IsAbs(pattern) && !strings.HasPrefix(pattern, "/"):
+		pattern = "**/" + pattern
+	}
+
+	return withTrailingStarStar(filepath.ToSlash(pattern)), prefix, nil
+}
+
+func (ctx IncludeContext) matchBranch(pattern string) bool {
+	branch := ctx.Branch
+	if strings.HasPrefix(branch, "refs/") {
+		var ok bool
+		branch, ok = strings.CutPrefix(branch, "refs/heads/")
+		if !ok {
+			return false
+		}
+	}
+	if len(branch) == 0 {
+		return false
+	}
+
+	return wildmatch(withTrailingStarStar(pattern), branch, wmPathname)
+}
+
+func (ctx IncludeContext) matchRemoteURL(pattern string) bool {
+	for _, url := range ctx.RemoteURLs {
+		if wildmatch(pattern, url, wmPathname) {
+			return true
+		}
+	}
+	return false
+}
+
+// withTrailingStarStar makes a pattern ending with '/' match everything inside that directory.
+func withTrailingStarStar(pattern string) string {
+	if strings.HasSuffix(pattern, "/") {
+		return pattern + "**"
+	}
+	return pattern
+}
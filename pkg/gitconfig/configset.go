@@ -0,0 +1,315 @@
+package gitconfig
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Scope tells which level of git's configuration a config belongs to.
+type Scope int
+
+const (
+	_ Scope = iota
+	ScopeSystem
+	ScopeGlobal
+	ScopeLocal
+	ScopeWorktree
+	ScopeCommand
+)
+
+var scopeString = []string{
+	"unknown",
+	"system",
+	"global",
+	"local",
+	"worktree",
+	"command",
+}
+
+func (s Scope) String() string {
+	if s < 0 || int(s) >= len(scopeString) {
+		return scopeString[0]
+	}
+	return scopeString[s]
+}
+
+// Origin tells where a value was read from.
+type Origin struct {
+	Scope Scope
+	File  string // empty if the value wasn't read from a file
+	Line  int    // 0 if the value wasn't read from a file
+}
+
+// String formats o like "git config --show-origin" does.
+func (o Origin) String() string {
+	if o.Scope == ScopeCommand && len(o.File) == 0 {
+		return "command line:"
+	}
+	return "file:" + o.File
+}
+
+// Entry is a value along with its key and origin.
+type Entry struct {
+	Key    Key
+	Value  Value
+	Origin Origin
+}
+
+type layer struct {
+	scope  Scope
+	config *GitConfig
+}
+
+// ConfigSet combines configs of different scopes the way git does. Lookups go
+// through them in git's order of precedence (system, global, local, worktree
+// and command line), so a value from a later scope overrides an earlier one.
+type ConfigSet struct {
+	layers []layer
+}
+
+// NewConfigSet creates an empty ConfigSet.
+func NewConfigSet() *ConfigSet {
+	return new(ConfigSet)
+}
+
+// LoadConfigSet loads every config file git would read when run inside the
+// repository whose .git directory is gitDir:
+//   - system: $GIT_CONFIG_SYSTEM or $(prefix)/etc/gitconfig, unless $GIT_CONFIG_NOSYSTEM is set
+//   - global: $GIT_CONFIG_GLOBAL, or $XDG_CONFIG_HOME/git/config and ~/.gitconfig
+//   - local: $GIT_DIR/config
+//   - worktree: $GIT_DIR/config.worktree, if extensions.worktreeConfig is enabled
+//   - command: the variables set through the environment, see ParseEnv()
+//
+// If gitDir is empty, the local and worktree configs aren't loaded. Files that
+// don't exist are skipped. Includes are resolved using opts.
+func LoadConfigSet(gitDir string, opts LoadOptions) (*ConfigSet, error) {
+	cs := NewConfigSet()
+
+	if noSystem, _ := parseBool(os.Getenv("GIT_CONFIG_NOSYSTEM")); !noSystem {
+		err := cs.AddFile(ScopeSystem, systemConfigPath(), opts)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	globals, err := globalConfigPaths()
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range globals {
+		err = cs.AddFile(ScopeGlobal, path, opts)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(gitDir) > 0 {
+		err = cs.AddFile(ScopeLocal, filepath.Join(commonDir(gitDir), "config"), opts)
+		if err != nil {
+			return nil, err
+		}
+		if cs.worktreeConfigEnabled() {
+			err = cs.AddFile(ScopeWorktree, filepath.Join(gitDir, "config.worktree"), opts)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	env, err := ParseEnv(os.Environ())
+	if err != nil {
+		return nil, err
+	}
+	if env.data.len() > 0 {
+		cs.Add(ScopeCommand, env)
+	}
+
+	return cs, nil
+}
+
+func systemConfigPath() string {
+	if path, ok := os.LookupEnv("GIT_CONFIG_SYSTEM"); ok {
+		return path
+	}
+	if prefix := gitPrefix(); prefix != "/usr" {
+		return filepath.Join(prefix, "etc", "gitconfig")
+	}
+	return "/etc/gitconfig"
+}
+
+// globalConfigPaths returns the global config files in the order git reads them.
+func globalConfigPaths() ([]string, error) {
+	if path, ok := os.LookupEnv("GIT_CONFIG_GLOBAL"); ok {
+		return []string{path}, nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, err
+	}
+	xdg := os.Getenv("XDG_CONFIG_HOME")
+	if len(xdg) == 0 {
+		xdg = filepath.Join(home, ".config")
+	}
+
+	return []string{
+		filepath.Join(xdg, "git", "config"),
+		filepath.Join(home, ".gitconfig"),
+	}, nil
+}
+
+// GlobalConfigPath returns the file "git config --global" reads and writes:
+// $GIT_CONFIG_GLOBAL if set, otherwise ~/.gitconfig, unless only
+// $XDG_CONFIG_HOME/git/config exists.
+func GlobalConfigPath() (string, error) {
+	paths, err := globalConfigPaths()
+	if err != nil {
+		return "", err
+	}
+	user := paths[len(paths)-1]
+	if len(paths) == 2 {
+		if _, err := os.Stat(user); err != nil {
+			if _, err := os.Stat(paths[0]); err == nil {
+				return paths[0], nil
+			}
+		}
+	}
+
+	return user, nil
+}
+
+// commonDir returns the directory shared by all worktrees of the repository,
+// which is where the local config is stored.
+func commonDir(gitDir string) string {
+	content, err := os.ReadFile(filepath.Join(gitDir, "commondir"))
+	if err != nil {
+		return gitDir
+	}
+	dir := strings.TrimSpace(string(content))
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(gitDir, dir)
+	}
+	return dir
+}
+
+func (cs ConfigSet) worktreeConfigEnabled() bool {
+	entry, err := cs.Get("extensions.worktreeConfig")
+	if err != nil || entry.Origin.Scope != ScopeLocal {
+		return false
+	}
+	enabled, _ := entry.Value.Bool()
+	return enabled
+}
+
+// Add adds config to the set as a config of the given scope.
+// Configs of the same scope are looked up in the order they're added.
+func (cs *ConfigSet) Add(scope Scope, config *GitConfig) {
+	i := len(cs.layers)
+	for i > 0 && cs.layers[i-1].scope > scope {
+		i--
+	}
+	cs.layers = slices.Insert(cs.layers, i, layer{scope, config})
+}
+
+// AddFile loads the config file at path with Load() and adds it to the set.
+// Nothing is added if the file doesn't exist.
+func (cs *ConfigSet) AddFile(scope Scope, path string, opts LoadOptions) error {
+	if len(path) == 0 {
+		return nil
+	}
+	config, err := Load(path, opts)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	cs.Add(scope, config)
+
+	return nil
+}
+
+// AddParameters adds values given in the "<key>=<value>" form of "git -c" to the
+// set, as values of the command line scope. A parameter without '=' sets the
+// key without a value, which is true as a boolean.
+func (cs *ConfigSet) AddParameters(params ...string) error {
+	config := New()
+	for _, param := range params {
+		key, val, hasValue := strings.Cut(param, "=")
+		var err error
+		if hasValue {
+			err = config.appendParameter(key, &val)
+		} else {
+			err = config.appendParameter(key, nil)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	cs.Add(ScopeCommand, config)
+
+	return nil
+}
+
+// Get retrieves the value of a given key along with its origin. If the key has
+// multiple values, the one with the highest precedence is returned.
+func (cs ConfigSet) Get(key string) (Entry, error) {
+	entries, err := cs.GetAll(key)
+	if err != nil {
+		return Entry{}, err
+	}
+
+	return entries[len(entries)-1], nil
+}
+
+// GetAll retrieves all values of a given key along with their origins, from
+// the lowest to the highest precedence.
+func (cs ConfigSet) GetAll(key string) ([]Entry, error) {
+	section, name, err := GitConfig{}.splitKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []Entry
+	for _, l := range cs.layers {
+		for _, e := range l.config.nodes(section, name) {
+			entries = append(entries, l.entry(e.val))
+		}
+	}
+	if len(entries) == 0 {
+		return nil, ErrKeyNotFound
+	}
+
+	return entries, nil
+}
+
+// Entries returns every value in the set, the same list
+// "git config --list --show-origin --show-scope" prints.
+func (cs ConfigSet) Entries() []Entry {
+	var entries []Entry
+	for _, l := range cs.layers {
+		for e := l.config.lines.front(); e != nil; e = e.next {
+			if e.val.typ == variable {
+				entries = append(entries, l.entry(e.val))
+			}
+		}
+	}
+
+	return entries
+}
+
+func (l layer) entry(line syntaxNode) Entry {
+	return Entry{
+		Key:   Key{line.section, line.name},
+		Value: line.value,
+		Origin: Origin{
+			Scope: l.scope,
+			File:  line.file,
+			Line:  line.lineNumber,
+		},
+	}
+}
@@ -0,0 +1,176 @@
+package gitconfig
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLoadConfigSet(t *testing.T) {
+	dir := t.TempDir()
+	home := filepath.Join(dir, "home")
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "")
+	t.Setenv("GIT_CONFIG_SYSTEM", filepath.Join(dir, "etc/gitconfig"))
+
+	writeConfigs(t, dir, map[string]string{
+		"etc/gitconfig":                          "[core]\n\tautocrlf = input\n[user]\n\tname = System\n",
+		"home/.config/git/config":                "[user]\n\tname = XDG\n",
+		"home/.gitconfig":                        "[user]\n\tname = Global\n\temail = global@example.com\n[include]\n\tpath = work.inc\n",
+		"home/work.inc":                          "[user]\n\temail = work@example.com\n",
+		"repo/.git/config":                       "[core]\n\tbare = false\n[extensions]\n\tworktreeConfig = true\n[user]\n\n\tname = Local\n",
+		"repo/.git/worktrees/wt/commondir":       "../..\n",
+		"repo/.git/worktrees/wt/config.worktree": "[user]\n\tname = Worktree\n",
+	})
+
+	tests := []struct {
+		name    string
+		gitDir  string
+		key     string
+		want    []Entry
+		wantErr error
+	}{
+		{
+			name: "System Only",
+			key:  "core.autocrlf",
+			want: []Entry{
+				{Key{Section{Name: "core", Subsection: ""}, "autocrlf"}, Value{"input"}, Origin{ScopeSystem, filepath.Join(dir, "etc/gitconfig"), 2}},
+			},
+		},
+		{
+			name: "Global Overrides System",
+			key:  "user.name",
+			want: []Entry{
+				{Key{Section{Name: "user", Subsection: ""}, "name"}, Value{"System"}, Origin{ScopeSystem, filepath.Join(dir, "etc/gitconfig"), 4}},
+				{Key{Section{Name: "user", Subsection: ""}, "name"}, Value{"XDG"}, Origin{ScopeGlobal, filepath.Join(home, ".config/git/config"), 2}},
+				{Key{Section{Name: "user", Subsection: ""}, "name"}, Value{"Global"}, Origin{ScopeGlobal, filepath.Join(home, ".gitconfig"), 2}},
+			},
+		},
+		{
+			name: "Included File",
+			key:  "user.email",
+			want: []Entry{
+				{Key{Section{Name: "user", Subsection: ""}, "email"}, Value{"global@example.com"}, Origin{ScopeGlobal, filepath.Join(home, ".gitconfig"), 3}},
+				{Key{Section{Name: "user", Subsection: ""}, "email"}, Value{"work@example.com"}, Origin{ScopeGlobal, filepath.Join(home, "work.inc"), 2}},
+			},
+		},
+		{
+			name:   "Local Overrides Global",
+			gitDir: filepath.Join(dir, "repo/.git"),
+			key:    "user.name",
+			want: []Entry{
+				{Key{Section{Name: "user", Subsection: ""}, "name"}, Value{"System"}, Origin{ScopeSystem, filepath.Join(dir, "etc/gitconfig"), 4}},
+				{Key{Section{Name: "user", Subsection: ""}, "name"}, Value{"XDG"}, Origin{ScopeGlobal, filepath.Join(home, ".config/git/config"), 2}},
+				{Key{Section{Name: "user", Subsection: ""}, "name"}, Value{"Global"}, Origin{ScopeGlobal, filepath.Join(home, ".gitconfig"), 2}},
+				{Key{Section{Name: "user", Subsection: ""}, "name"}, Value{"Local"}, Origin{ScopeLocal, filepath.Join(dir, "repo/.git/config"), 7}},
+			},
+		},
+		{
+			name:   "Worktree Overrides Local",
+			gitDir: filepath.Join(dir, "repo/.git/worktrees/wt"),
+			key:    "user.name",
+			want: []Entry{
+				{Key{Section{Name: "user", Subsection: ""}, "name"}, Value{"System"}, Origin{ScopeSystem, filepath.Join(dir, "etc/gitconfig"), 4}},
+				{Key{Section{Name: "user", Subsection: ""}, "name"}, Value{"XDG"}, Origin{ScopeGlobal, filepath.Join(home, ".config/git/config"), 2}},
+				{Key{Section{Name: "user", Subsection: ""}, "name"}, Value{"Global"}, Origin{ScopeGlobal, filepath.Join(home, ".gitconfig"), 2}},
+				{Key{Section{Name: "user", Subsection: ""}, "name"}, Value{"Local"}, Origin{ScopeLocal, filepath.Join(dir, "repo/.git/worktrees/wt/../../config"), 7}},
+				{Key{Section{Name: "user", Subsection: ""}, "name"}, Value{"Worktree"}, Origin{ScopeWorktree, filepath.Join(dir, "repo/.git/worktrees/wt/config.worktree"), 2}},
+			},
+		},
+		{
+			name:    "Key Not Found",
+			gitDir:  filepath.Join(dir, "repo/.git"),
+			key:     "user.signingkey",
+			wantErr: ErrKeyNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cs, err := LoadConfigSet(tt.gitDir, LoadOptions{})
+			if err != nil {
+				t.Fatalf("LoadConfigSet() error = %v, want %v", err, nil)
+			}
+
+			got, err := cs.GetAll(tt.key)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ConfigSet.GetAll() error = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ConfigSet.GetAll() = %v, want %v", got, tt.want)
+			}
+
+			last, err := cs.Get(tt.key)
+			if len(tt.want) > 0 && (err != nil || !reflect.DeepEqual(last, tt.want[len(tt.want)-1])) {
+				t.Errorf("ConfigSet.Get() = (%v, %v), want (%v, %v)", last, err, tt.want[len(tt.want)-1], nil)
+			}
+		})
+	}
+}
+
+func TestConfigSet_AddParameters(t *testing.T) {
+	local, err := Parse([]byte("[user]\n\tname = Local\n[core]\n\tbare = true\n"))
+	if err != nil {
+		t.Fatalf("Parse() error = %v, want %v", err, nil)
+	}
+
+	cs := NewConfigSet()
+	err = cs.AddParameters("user.name=Command Line", "core.bare=false", "commit.gpgSign")
+	if err != nil {
+		t.Fatalf("ConfigSet.AddParameters() error = %v, want %v", err, nil)
+	}
+	// added after the command line values, but still has a lower precedence
+	cs.Add(ScopeLocal, local)
+
+	want := []Entry{
+		{Key{Section{Name: "user", Subsection: ""}, "name"}, Value{"Local"}, Origin{ScopeLocal, "", 2}},
+		{Key{Section{Name: "core", Subsection: ""}, "bare"}, Value{"true"}, Origin{ScopeLocal, "", 4}},
+		{Key{Section{Name: "user", Subsection: ""}, "name"}, Value{"Command Line"}, Origin{Scope: ScopeCommand}},
+		{Key{Section{Name: "core", Subsection: ""}, "bare"}, Value{"false"}, Origin{Scope: ScopeCommand}},
+		{Key{Section{Name: "commit", Subsection: ""}, "gpgSign"}, Value{}, Origin{Scope: ScopeCommand}},
+	}
+	if got := cs.Entries(); !reflect.DeepEqual(got, want) {
+		t.Errorf("ConfigSet.Entries() = %v, want %v", got, want)
+	}
+
+	entry, err := cs.Get("user.name")
+	if err != nil || entry.Value.String() != "Command Line" || entry.Origin.String() != "command line:" {
+		t.Errorf("ConfigSet.Get() = (%v, %v), want value %q from %q", entry, err, "Command Line", "command line:")
+	}
+
+	err = cs.AddParameters("user")
+	if !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("ConfigSet.AddParameters() error = %v, want %v", err, ErrInvalidKey)
+	}
+}
+
+func TestGlobalConfigPath(t *testing.T) {
+	dir := t.TempDir()
+	home := filepath.Join(dir, "home")
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(dir, "xdg"))
+
+	tests := []struct {
+		name   string
+		files  map[string]string
+		global string
+		want   string
+	}{
+		{name: "neither exists", want: filepath.Join(home, ".gitconfig")},
+		{name: "only xdg exists", files: map[string]string{"xdg/git/config": ""}, want: filepath.Join(dir, "xdg/git/config")},
+		{name: "both exist", files: map[string]string{"home/.gitconfig": ""}, want: filepath.Join(home, ".gitconfig")},
+		{name: "GIT_CONFIG_GLOBAL", global: filepath.Join(dir, "global"), want: filepath.Join(dir, "global")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writeConfigs(t, dir, tt.files)
+			if len(tt.global) > 0 {
+				t.Setenv("GIT_CONFIG_GLOBAL", tt.global)
+			}
+			if got, err := GlobalConfigPath(); err != nil || got != tt.want {
+				t.Errorf("GlobalConfigPath() = (%q, %v), want (%q, %v)", got, err, tt.want, nil)
+			}
+		})
+	}
+}
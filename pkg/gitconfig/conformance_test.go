@@ -0,0 +1,202 @@
+package gitconfig
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// conformanceHeaders, conformanceVariables and conformanceLineEndings are
+// combined into the config files generated by conformanceCases.
+var (
+	conformanceHeaders = map[string]string{
+		"plain":              "[core]",
+		"mixed case":         "[CoRe]",
+		"subsection":         `[remote "origin"]`,
+		"subsection case":    `[Remote "OriGin"]`,
+		"subsection spaces":  `[remote "my origin"]`,
+		"subsection dot":     `[url "git@github.com:"]`,
+		"subsection escape":  `[remote "a\"b"]`,
+		"subsection slash":   `[remote "a\\b"]`,
+		"subsection other":   `[remote "a\zb"]`,
+		"subsection quote":   `[remote "a"b"]`,
+		"deprecated dot":     "[remote.Origin]",
+		"indented":           "  \t[core]",
+		"trailing comment":   "[core] ; comment",
+		"trailing variable":  "[core] bare = true",
+		"dash":               "[my-section]",
+		"unclosed":           "[core",
+		"unclosed quote":     `[remote "origin]`,
+		"space no quote":     "[remote origin]",
+		"invalid name":       "[co_re]",
+		"empty":              "[]",
+		"empty subsection":   `[remote ""]`,
+		"newline subsection": `[remote "a\nb"]`,
+	}
+	conformanceVariables = map[string]string{
+		"plain":                   "name = value",
+		"no spaces":               "name=value",
+		"mixed case":              "NaMe = VaLuE",
+		"dash":                    "my-name = value",
+		"bare":                    "name",
+		"bare spaces":             "name  ",
+		"empty":                   "name =",
+		"empty spaces":            "name =   ",
+		"inner spaces":            "name = a  b",
+		"inner tab":               "name = a\tb",
+		"trailing spaces":         "name = value   ",
+		"quoted":                  `name = "value"`,
+		"quoted spaces":           `name = "  a  b  "`,
+		"partly quoted":           `name = a" b "c`,
+		"quoted comment":          `name = "a # b"`,
+		"comment hash":            "name = value # comment",
+		"comment semicolon":       "name = value ; comment",
+		"comment no space":        "name = value#comment",
+		"escape quote":            `name = a\"b`,
+		"escape backslash":        `name = a\\b`,
+		"escape newline":          `name = a\nb`,
+		"escape tab":              `name = a\tb`,
+		"escape backspace":        `name = a\bb`,
+		"invalid escape":          `name = a\zb`,
+		"continuation":            "name = a \\\n  b",
+		"continuation quoted":     "name = \"a \\\n  b\"",
+		"continuation twice":      "name = a\\\nb\\\nc",
+		"continuation end":        "name = a\\",
+		"continuation comment":    "name = a \\\n# not a comment",
+		"unclosed quote":          `name = "value`,
+		"indented":                "\t  name = value",
+		"invalid name":            "na_me = value",
+		"starts with digit":       "1name = value",
+		"space in name":           "na me = value",
+		"equals in value":         "name = a=b",
+		"spaces around equals":    "name\t=\tvalue",
+		"unicode":                 "name = héllo wörld",
+		"quote at end":            `name = value"`,
+		"escaped quote in quotes": `name = "a\"b"`,
+	}
+	conformanceLineEndings = map[string]string{
+		"LF":   "\n",
+		"CRLF": "\r\n",
+	}
+)
+
+// conformanceCases returns config files made of every combination of a
+// header, a variable and a line ending, along with a few whole files.
+func conformanceCases() map[string]string {
+	cases := map[string]string{
+		"empty file":              "",
+		"no trailing newline":     "[core]\n\tbare = true",
+		"comments only":           "# a\n; b\n",
+		"blank lines":             "\n\n[core]\n\n\tbare\n\n",
+		"repeated sections":       "[core]\n\ta = 1\n[user]\n\tname = x\n[core]\n\ta = 2\n",
+		"multi-valued":            "[remote \"origin\"]\n\tfetch = a\n\tfetch = b\n",
+		"variable before section": "name = value\n[core]\n\tbare\n",
+		"crlf continuation":       "[core]\r\n\tname = a\\\r\n\tb\r\n",
+		"mixed line endings":      "[core]\r\n\ta = 1\n\tb = 2\r\n",
+	}
+	for headerName, header := range conformanceHeaders {
+		for variableName, variable := range conformanceVariables {
+			for endingName, ending := range conformanceLineEndings {
+				name := fmt.Sprintf("header %s/variable %s/%s", headerName, variableName, endingName)
+				config := header + "\n\t" + variable + "\n"
+				cases[name] = strings.ReplaceAll(config, "\n", ending)
+			}
+		}
+	}
+	return cases
+}
+
+// knownDivergences lists where Parse() doesn't behave like git, along with
+// why. Keys are the name of a whole file case or of a header or variable
+// (e.g. "variable inner tab"), which covers every case generated with it.
+// An entry that doesn't cover any diverging case fails the test, so that
+// it's removed once Parse() is fixed.
+var knownDivergences = map[string]string{
+	"variable before section":       `a variable outside of any section is listed as ".name", git lists it as "name"`,
+	"variable inner tab":            `unquoted tabs are kept, git turns them into spaces`,
+	"crlf continuation":             `unquoted tabs are kept, git turns them into spaces`,
+	"variable continuation comment": `whitespace before a continuation followed by a comment is trimmed, git keeps it`,
+}
+
+// listNull prints the variables of g like "git config --list --null".
+func listNull(g *GitConfig) string {
+	var sb strings.Builder
+	for e := g.lines.front(); e != nil; e = e.next {
+		if e.val.typ != variable {
+			continue
+		}
+		sec := e.val.section.canonical()
+		sb.WriteString(Key{sec, e.val.name.canonical()}.String())
+		if e.val.value.HasValue() {
+			sb.WriteString("\n" + e.val.value.String())
+		}
+		sb.WriteByte(0)
+	}
+	return sb.String()
+}
+
+func TestParse_Conformance(t *testing.T) {
+	if testing.Short() {
+		t.Skip("runs git for every case")
+	}
+	git, err := exec.LookPath("git")
+	if err != nil {
+		t.Skip("git isn't installed")
+	}
+
+	dir := t.TempDir()
+	used := make(map[string]bool)
+	cases := conformanceCases()
+	ran := 0
+	for name, config := range cases {
+		t.Run(name, func(t *testing.T) {
+			ran++
+			path := filepath.Join(dir, "config")
+			err := os.WriteFile(path, []byte(config), 0o644)
+			if err != nil {
+				t.Fatalf("os.WriteFile(%s) error = %v, want %v", path, err, nil)
+			}
+			cmd := exec.Command(git, "config", "--file", path, "--list", "--null")
+			cmd.Env = append(os.Environ(), "GIT_CONFIG_NOSYSTEM=1", "GIT_CONFIG_GLOBAL=/dev/null")
+			var stderr bytes.Buffer
+			cmd.Stderr = &stderr
+			want, gitErr := cmd.Output()
+
+			gc, err := Parse([]byte(config))
+			var diverges string
+			switch {
+			case gitErr != nil && err == nil:
+				diverges = fmt.Sprintf("Parse() error = %v, want an error like git's: %s", err, bytes.TrimSpace(stderr.Bytes()))
+			case gitErr == nil && err != nil:
+				diverges = fmt.Sprintf("Parse() error = %v, want %v", err, nil)
+			case err == nil && listNull(gc) != string(want):
+				diverges = fmt.Sprintf("Parse() = %q, want %q", listNull(gc), want)
+			}
+
+			if len(diverges) == 0 {
+				return
+			}
+			for _, part := range strings.Split(name, "/") {
+				if reason, ok := knownDivergences[part]; ok {
+					used[part] = true
+					t.Skipf("known divergence: %s", reason)
+				}
+			}
+			t.Errorf("%q: %s", config, diverges)
+		})
+	}
+
+	// with -run, the cases that would use an entry may not have run
+	if ran < len(cases) {
+		return
+	}
+	for name := range knownDivergences {
+		if !used[name] {
+			t.Errorf("knownDivergences[%q]: Parse() behaves like git, remove it from the list", name)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package gitconfig
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// now is replaced in tests.
+var now = time.Now
+
+var dateUnits = map[string]time.Duration{
+	"second": time.Second,
+	"minute": time.Minute,
+	"hour":   time.Hour,
+	"day":    24 * time.Hour,
+	"week":   7 * 24 * time.Hour,
+}
+
+// maxTime is the latest time a time.Time can hold, git's TIME_MAX.
+var maxTime = time.Unix(1<<63-62135596801, 999999999)
+
+var dateLayouts = []string{
+	time.RFC3339,
+	time.RFC1123Z,
+	time.RFC1123,
+	"2006-01-02 15:04:05 -0700",
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04:05",
+	"2006-01-02",
+	"2006.01.02",
+	"01/02/2006",
+}
+
+// ExpiryDate interprets the value as an expiry date such as the ones used by
+// gc.reflogExpire or gc.pruneExpire. Like git, "never" and "false" return the
+// zero time (nothing expires), and "now" and "all" return the latest possible
+// time (everything expires). These are case-sensitive, other values are read
+// like git's approxidate, ignoring case: "Never" is the zero time and "Now" is
+// the current time. Relative dates ("2.weeks.ago", "3 months ago",
+// "yesterday"), unix timestamps ("@1700000000") and absolute dates
+// ("2024-01-31", RFC 3339) are supported.
+func (val Value) ExpiryDate() (time.Time, error) {
+	switch val.String() {
+	case "never", "false":
+		return time.Time{}, nil
+	case "now", "all":
+		return maxTime, nil
+	}
+
+	raw := strings.TrimSpace(val.String())
+	s := strings.ToLower(raw)
+	current := now()
+	switch s {
+	case "never":
+		return time.Time{}, nil
+	case "now":
+		return current, nil
+	case "yesterday":
+		return current.AddDate(0, 0, -1), nil
+	}
+
+	if ts, ok := strings.CutPrefix(s, "@"); ok {
+		sec, err := strconv.ParseInt(ts, 10, 64)
+		if err != nil {
+			return time.Time{}, ErrInvalidDate
+		}
+		return time.Unix(sec, 0), nil
+	}
+
+	for _, layout := range dateLayouts {
+		t, err := time.ParseInLocation(layout, raw, current.Location())
+		if err == nil {
+			return t, nil
+		}
+	}
+
+	return parseRelativeDate(s, current)
+}
+
+// parseRelativeDate parses dates such as "2.weeks.ago" or "1 year 6 months ago".
+func parseRelativeDate(s string, current time.Time) (time.Time, error) {
+	words := strings.FieldsFunc(s, func(r rune) bool {
+		return r == '.' || r == ' ' || r == '\t' || r == ','
+	})
+	if len(words) > 0 && words[len(words)-1] == "ago" {
+		words = words[:len(words)-1]
+	}
+	if len(words) == 0 || len(words)%2 != 0 {
+		return time.Time{}, ErrInvalidDate
+	}
+
+	t := current
+	for i := 0; i < len(words); i += 2 {
+		n, err := strconv.Atoi(words[i])
+		if err != nil || n < 0 {
+			return time.Time{}, ErrInvalidDate
+		}
+		unit := strings.TrimSuffix(words[i+1], "s")
+		switch unit {
+		case "month":
+			t = t.AddDate(0, -n, 0)
+		case "year":
+			t = t.AddDate(-n, 0, 0)
+		default:
+			d, ok := dateUnits[unit]
+			if !ok {
+				return time.Time{}, ErrInvalidDate
+			}
+			t = t.Add(-time.Duration(n) * d)
+		}
+	}
+
+	return t, nil
+}
@@ -0,0 +1,163 @@
+package gitconfig
+
+import "strings"
+
+// ChangeType tells how a key differs between two configs.
+type ChangeType int
+
+const (
+	_ ChangeType = iota
+	Added
+	Removed
+	Changed
+)
+
+var changeTypeString = []string{
+	"unknown",
+	"added",
+	"removed",
+	"changed",
+}
+
+func (ct ChangeType) String() string {
+	if ct < 0 || int(ct) >= len(changeTypeString) {
+		return changeTypeString[0]
+	}
+	return changeTypeString[ct]
+}
+
+// Change is a key that differs between two configs, along with all of its
+// values in both configs. Old is empty for an added key and New is empty for
+// a removed one.
+type Change struct {
+	Type     ChangeType
+	Key      Key
+	Old, New []Value
+}
+
+// Changes is the result of Diff().
+type Changes []Change
+
+// Diff compares config a to config b key by key. Values are compared by what
+// they mean rather than how they're written, so `"foo"` and `foo` are equal.
+// The values of a multi-valued key are compared in order, so reordering them
+// is a change, as it may change which value git uses.
+//
+// Removed and changed keys come first, in the order of a, followed by the
+// added keys in the order of b. A nil config is treated as an empty one.
+func Diff(a, b *GitConfig) Changes {
+	if a == nil {
+		a = New()
+	}
+	if b == nil {
+		b = New()
+	}
+
+	changes := make(Changes, 0)
+	for _, key := range a.Keys() {
+		old, _ := a.get(key.Section, key.Name)
+		vals, err := b.get(key.Section, key.Name)
+		switch {
+		case err != nil:
+			changes = append(changes, Change{Type: Removed, Key: key, Old: old})
+		case !equalValues(old, vals):
+			first := b.nodes(key.Section, key.Name)[0].val
+			changes = append(changes, Change{Type: Changed, Key: Key{first.section, first.name}, Old: old, New: vals})
+		}
+	}
+	for _, key := range b.Keys() {
+		if len(a.nodes(key.Section, key.Name)) > 0 {
+			continue
+		}
+		vals, _ := b.get(key.Section, key.Name)
+		changes = append(changes, Change{Type: Added, Key: key, New: vals})
+	}
+
+	return changes
+}
+
+func equalValues(a, b []Value) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !equalValue(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func equalValue(a, b Value) bool {
+	return a.HasValue() == b.HasValue() && a.String() == b.String()
+}
+
+// String renders the changes like Unified("a", "b") does.
+func (c Changes) String() string {
+	return c.Unified("a", "b")
+}
+
+// Unified renders the changes as a unified diff of the variables, printed like
+// "git config --list" does. Values of a changed key that are the same in both
+// configs are printed as context, prefixed with a space.
+func (c Changes) Unified(oldName, newName string) string {
+	if len(c) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString("--- " + oldName + "\n")
+	sb.WriteString("+++ " + newName + "\n")
+	for _, change := range c {
+		for _, line := range change.lines() {
+			sb.WriteString(line + "\n")
+		}
+	}
+
+	return sb.String()
+}
+
+// lines returns the lines of the unified diff of c.
+func (c Change) lines() []string {
+	key := c.Key.String()
+	variable := func(prefix string, val Value) string {
+		if !val.HasValue() {
+			return prefix + key
+		}
+		return prefix + key + "=" + val.String()
+	}
+
+	// longest common subsequence of the values, ...
+	lcs := make([][]int, len(c.Old)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(c.New)+1)
+	}
+	for i := len(c.Old) - 1; i >= 0; i-- {
+		for j := len(c.New) - 1; j >= 0; j-- {
+			if equalValue(c.Old[i], c.New[j]) {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	// ... which is printed as context between the removed and added values
+	lines := make([]string, 0, len(c.Old)+len(c.New))
+	i, j := 0, 0
+	for i < len(c.Old) || j < len(c.New) {
+		switch {
+		case i < len(c.Old) && j < len(c.New) && equalValue(c.Old[i], c.New[j]):
+			lines = append(lines, variable(" ", c.Old[i]))
+			i, j = i+1, j+1
+		case j == len(c.New) || (i < len(c.Old) && lcs[i+1][j] >= lcs[i][j+1]):
+			lines = append(lines, variable("-", c.Old[i]))
+			i++
+		default:
+			lines = append(lines, variable("+", c.New[j]))
+			j++
+		}
+	}
+
+	return lines
+}
@@ -0,0 +1,86 @@
+package gitconfig
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want Changes
+	}{
+		{
+			name: "Equal",
+			a:    "[user]\n\tname = John\n",
+			b:    "[USER]\n\tNAME = \"John\" # same value\n",
+			want: Changes{},
+		},
+		{
+			name: "Added Removed And Changed",
+			a:    "[user]\n\tname = John\n\temail = john@example.com\n[core]\n\teditor = vim\n",
+			b:    "[user]\n\temail = john@work.com\n\tname = John\n[commit]\n\tgpgsign\n",
+			want: Changes{
+				{Type: Changed, Key: Key{Section{Name: "user", Subsection: ""}, "email"}, Old: []Value{{"john@example.com"}}, New: []Value{{"john@work.com"}}},
+				{Type: Removed, Key: Key{Section{Name: "core", Subsection: ""}, "editor"}, Old: []Value{{"vim"}}},
+				{Type: Added, Key: Key{Section{Name: "commit", Subsection: ""}, "gpgsign"}, New: []Value{{}}},
+			},
+		},
+		{
+			name: "Reordered Values",
+			a:    "[include]\n\tpath = a\n\tpath = b\n",
+			b:    "[include]\n\tpath = b\n\tpath = a\n",
+			want: Changes{
+				{Type: Changed, Key: Key{Section{Name: "include", Subsection: ""}, "path"}, Old: []Value{{"a"}, {"b"}}, New: []Value{{"b"}, {"a"}}},
+			},
+		},
+		{
+			name: "No Value Is Not Empty",
+			a:    "[core]\n\tbare\n",
+			b:    "[core]\n\tbare =\n",
+			want: Changes{
+				{Type: Changed, Key: Key{Section{Name: "core", Subsection: ""}, "bare"}, Old: []Value{{}}, New: []Value{{""}}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := Parse([]byte(tt.a))
+			if err != nil {
+				t.Fatalf("Parse() error = %v, want %v", err, nil)
+			}
+			b, err := Parse([]byte(tt.b))
+			if err != nil {
+				t.Fatalf("Parse() error = %v, want %v", err, nil)
+			}
+			if got := Diff(a, b); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Diff() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestChanges_Unified(t *testing.T) {
+	a, _ := Parse([]byte("[include]\n\tpath = a\n\tpath = b\n\tpath = c\n[user]\n\tname = John\n[core]\n\tbare\n"))
+	b, _ := Parse([]byte("[include]\n\tpath = a\n\tpath = c\n\tpath = d\n[user]\n\tname = Jane\n"))
+
+	want := "--- default\n" +
+		"+++ work\n" +
+		" include.path=a\n" +
+		"-include.path=b\n" +
+		" include.path=c\n" +
+		"+include.path=d\n" +
+		"-user.name=John\n" +
+		"+user.name=Jane\n" +
+		"-core.bare\n"
+	if got := Diff(a, b).Unified("default", "work"); got != want {
+		t.Errorf("Changes.Unified() = %q, want %q", got, want)
+	}
+	if got := Diff(a, a).String(); got != "" {
+		t.Errorf("Changes.String() = %q, want %q", got, "")
+	}
+	if got := Diff(nil, b); len(got) != 2 || got[0].Type != Added {
+		t.Errorf("Diff() = %v, want only added keys", got)
+	}
+}
@@ -0,0 +1,225 @@
+package gitconfig
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ParseEnv reads the variables git is given through environ, a list of
+// "KEY=value" strings like os.Environ() returns. Like git, the variables set by
+// GIT_CONFIG_COUNT, GIT_CONFIG_KEY_<n> and GIT_CONFIG_VALUE_<n> come first,
+// followed by the ones of GIT_CONFIG_PARAMETERS, which is what "git -c" sets.
+func ParseEnv(environ []string) (*GitConfig, error) {
+	env := make(map[string]string, len(environ))
+	for _, kv := range environ {
+		k, v, _ := strings.Cut(kv, "=")
+		env[k] = v
+	}
+
+	g := New()
+	if count, ok := env["GIT_CONFIG_COUNT"]; ok {
+		n, err := parseEnvCount(count)
+		if err != nil {
+			return nil, err
+		}
+		for i := range n {
+			key, ok := env[fmt.Sprintf("GIT_CONFIG_KEY_%d", i)]
+			if !ok {
+				return nil, fmt.Errorf("%w: missing config key GIT_CONFIG_KEY_%d", ErrInvalidEnv, i)
+			}
+			val, ok := env[fmt.Sprintf("GIT_CONFIG_VALUE_%d", i)]
+			if !ok {
+				return nil, fmt.Errorf("%w: missing config value GIT_CONFIG_VALUE_%d", ErrInvalidEnv, i)
+			}
+			err = g.appendParameter(key, &val)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if params, ok := env["GIT_CONFIG_PARAMETERS"]; ok {
+		err := g.appendParameters(params)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return g, nil
+}
+
+// parseEnvCount parses the value of GIT_CONFIG_COUNT the way git does with
+// strtoul(): an empty value is 0, leading whitespace and a sign are allowed
+// and a negative count is too large.
+func parseEnvCount(count string) (int, error) {
+	if len(count) == 0 {
+		return 0, nil
+	}
+
+	digits := strings.TrimLeft(count, " \t\n\v\f\r")
+	negative := false
+	if len(digits) > 0 && (digits[0] == '+' || digits[0] == '-') {
+		negative = digits[0] == '-'
+		digits = digits[1:]
+	}
+	if len(digits) == 0 || strings.ContainsFunc(digits, func(r rune) bool { return !isNum(r) }) {
+		return 0, fmt.Errorf("%w: bogus count in GIT_CONFIG_COUNT", ErrInvalidEnv)
+	}
+	n, err := strconv.ParseUint(digits, 10, 31)
+	if err != nil || negative && n > 0 {
+		return 0, fmt.Errorf("%w: too many entries in GIT_CONFIG_COUNT", ErrInvalidEnv)
+	}
+
+	return int(n), nil
+}
+
+// appendParameters appends the variables of params, the value of GIT_CONFIG_PARAMETERS.
+// Each parameter is either 'key'='value' or 'key=value', quoted like sqQuote() does.
+// 'key'= and 'key' set key without a value.
+func (g *GitConfig) appendParameters(params string) error {
+	bogus := fmt.Errorf("%w: bogus format in GIT_CONFIG_PARAMETERS", ErrInvalidEnv)
+	rest := strings.TrimLeft(params, " \t\n\v\f\r")
+	for len(rest) > 0 {
+		key, r, ok := sqDequote(rest)
+		if !ok {
+			return bogus
+		}
+
+		var val *string
+		switch {
+		case len(r) == 0 || isSpace(r[0]):
+			k, v, hasValue := strings.Cut(key, "=")
+			key = k
+			if hasValue {
+				val = &v
+			}
+		case r[0] == '=':
+			r = r[1:]
+			if len(r) == 0 || isSpace(r[0]) {
+				break
+			}
+			v, after, ok := sqDequote(r)
+			if !ok || (len(after) > 0 && !isSpace(after[0])) {
+				return bogus
+			}
+			val, r = &v, after
+		default:
+			return bogus
+		}
+
+		err := g.appendParameter(key, val)
+		if err != nil {
+			return err
+		}
+		rest = strings.TrimLeft(r, " \t\n\v\f\r")
+	}
+
+	return nil
+}
+
+// appendParameter appends the variable key, given on the command line or
+// through the environment. A nil val sets key without a value.
+func (g *GitConfig) appendParameter(key string, val *string) error {
+	section, name, err := g.splitKey(key)
+	if err != nil {
+		return fmt.Errorf("%w: %s", err, key)
+	}
+	value := Value{}
+	if val != nil {
+		value = Value{EncodeValue(*val)}
+	}
+	g.appendVariable(newVariableLine(section, name, value, "\t"))
+
+	return nil
+}
+
+// Environ returns the environment variables that give the variables of g to
+// git: GIT_CONFIG_COUNT, followed by GIT_CONFIG_KEY_<n> and GIT_CONFIG_VALUE_<n>
+// for every variable. As this form can't tell them apart, a variable without a
+// value (e.g. "[core] bare") is given as "true".
+func (g GitConfig) Environ() []string {
+	var (
+		env []string
+		n   int
+	)
+	for e := g.lines.front(); e != nil; e = e.next {
+		if e.val.typ != variable {
+			continue
+		}
+		val := "true"
+		if e.val.value.HasValue() {
+			val = e.val.value.String()
+		}
+		env = append(env,
+			fmt.Sprintf("GIT_CONFIG_KEY_%d=%s", n, Key{e.val.section, e.val.name}),
+			fmt.Sprintf("GIT_CONFIG_VALUE_%d=%s", n, val),
+		)
+		n++
+	}
+
+	return append([]string{fmt.Sprintf("GIT_CONFIG_COUNT=%d", n)}, env...)
+}
+
+// EncodeParameters returns the variables of g as the value of
+// GIT_CONFIG_PARAMETERS, written the way "git -c" does, e.g. 'user.name'='John Doe'.
+func (g GitConfig) EncodeParameters() string {
+	params := make([]string, 0)
+	for e := g.lines.front(); e != nil; e = e.next {
+		if e.val.typ != variable {
+			continue
+		}
+		param := sqQuote(Key{e.val.section, e.val.name}.String()) + "="
+		if e.val.value.HasValue() {
+			param += sqQuote(e.val.value.String())
+		}
+		params = append(params, param)
+	}
+
+	return strings.Join(params, " ")
+}
+
+// sqQuote quotes s for the shell the way git does, with ' and ! written
+// outside of the quotes.
+func sqQuote(s string) string {
+	var sb strings.Builder
+	sb.WriteByte('\'')
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\'', '!':
+			sb.WriteString(`'\`)
+			sb.WriteByte(s[i])
+			sb.WriteByte('\'')
+		default:
+			sb.WriteByte(s[i])
+		}
+	}
+	sb.WriteByte('\'')
+
+	return sb.String()
+}
+
+// sqDequote reads the word quoted by sqQuote() at the start of s and returns it
+// along with what follows it. ok is false if s doesn't start with a quoted word.
+func sqDequote(s string) (word, rest string, ok bool) {
+	if !strings.HasPrefix(s, "'") {
+		return "", "", false
+	}
+
+	var sb strings.Builder
+	for i := 1; i < len(s); i++ {
+		if s[i] != '\'' {
+			sb.WriteByte(s[i])
+			continue
+		}
+		// out of the quotes, only an escaped ' or ! followed by a quote continues the word
+		if i+3 < len(s) && s[i+1] == '\\' && (s[i+2] == '\'' || s[i+2] == '!') && s[i+3] == '\'' {
+			sb.WriteByte(s[i+2])
+			i += 3
+			continue
+		}
+		return sb.String(), s[i+1:], true
+	}
+
+	return "", "", false
+}
@@ -0,0 +1,189 @@
+package gitconfig
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"slices"
+	"testing"
+)
+
+func TestParseEnv(t *testing.T) {
+	tests := []struct {
+		name    string
+		environ []string
+		want    [][2]string // key and value, "<nil>" for a key without a value
+		wantErr error
+	}{
+		{
+			name:    "count",
+			environ: []string{"GIT_CONFIG_COUNT=2", "GIT_CONFIG_KEY_0=user.name", "GIT_CONFIG_VALUE_0=John Doe", "GIT_CONFIG_KEY_1=Core.Bare", "GIT_CONFIG_VALUE_1="},
+			want:    [][2]string{{"user.name", "John Doe"}, {"Core.Bare", ""}},
+		},
+		{
+			name:    "empty count",
+			environ: []string{"GIT_CONFIG_COUNT=", "GIT_CONFIG_KEY_0=user.name", "GIT_CONFIG_VALUE_0=x"},
+		},
+		{
+			name:    "count with a sign and spaces",
+			environ: []string{"GIT_CONFIG_COUNT= +1", "GIT_CONFIG_KEY_0=user.name", "GIT_CONFIG_VALUE_0=x"},
+			want:    [][2]string{{"user.name", "x"}},
+		},
+		{
+			name:    "negative zero count",
+			environ: []string{"GIT_CONFIG_COUNT=-0", "GIT_CONFIG_KEY_0=user.name", "GIT_CONFIG_VALUE_0=x"},
+		},
+		{
+			name:    "parameters",
+			environ: []string{`GIT_CONFIG_PARAMETERS='user.name'='John Doe' 'remote.Origin.url'='a b'  'core.bare'=`},
+			want:    [][2]string{{"user.name", "John Doe"}, {"remote.Origin.url", "a b"}, {"core.bare", "<nil>"}},
+		},
+		{
+			name:    "old style parameters",
+			environ: []string{`GIT_CONFIG_PARAMETERS='user.name=John Doe' 'core.bare'`},
+			want:    [][2]string{{"user.name", "John Doe"}, {"core.bare", "<nil>"}},
+		},
+		{
+			name:    "escaped parameters",
+			environ: []string{`GIT_CONFIG_PARAMETERS='a.b'='it'\''s'\!'' 'a.c'='' 'a.d'='"x" \ ;y'`},
+			want:    [][2]string{{"a.b", "it's!"}, {"a.c", ""}, {"a.d", `"x" \ ;y`}},
+		},
+		{
+			name: "count before parameters",
+			environ: []string{
+				"GIT_CONFIG_PARAMETERS='a.b'='2'",
+				"GIT_CONFIG_COUNT=1", "GIT_CONFIG_KEY_0=a.b", "GIT_CONFIG_VALUE_0=1",
+			},
+			want: [][2]string{{"a.b", "1"}, {"a.b", "2"}},
+		},
+		{
+			name:    "bogus count",
+			environ: []string{"GIT_CONFIG_COUNT=two"},
+			wantErr: ErrInvalidEnv,
+		},
+		{
+			name:    "trailing space after count",
+			environ: []string{"GIT_CONFIG_COUNT=1 "},
+			wantErr: ErrInvalidEnv,
+		},
+		{
+			name:    "negative count",
+			environ: []string{"GIT_CONFIG_COUNT=-1"},
+			wantErr: ErrInvalidEnv,
+		},
+		{
+			name:    "count too large",
+			environ: []string{"GIT_CONFIG_COUNT=2147483648"},
+			wantErr: ErrInvalidEnv,
+		},
+		{
+			name:    "missing value",
+			environ: []string{"GIT_CONFIG_COUNT=1", "GIT_CONFIG_KEY_0=a.b"},
+			wantErr: ErrInvalidEnv,
+		},
+		{
+			name:    "invalid key",
+			environ: []string{"GIT_CONFIG_COUNT=1", "GIT_CONFIG_KEY_0=ab", "GIT_CONFIG_VALUE_0=c"},
+			wantErr: ErrInvalidKey,
+		},
+		{
+			name:    "unquoted parameter",
+			environ: []string{"GIT_CONFIG_PARAMETERS=a.b=c"},
+			wantErr: ErrInvalidEnv,
+		},
+		{
+			name:    "unclosed quote",
+			environ: []string{"GIT_CONFIG_PARAMETERS='a.b'='c"},
+			wantErr: ErrInvalidEnv,
+		},
+		{
+			name:    "garbage after value",
+			environ: []string{"GIT_CONFIG_PARAMETERS='a.b'='c'd"},
+			wantErr: ErrInvalidEnv,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gc, err := ParseEnv(tt.environ)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ParseEnv() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			var got [][2]string
+			for e := gc.lines.front(); e != nil; e = e.next {
+				if e.val.typ != variable {
+					continue
+				}
+				val := "<nil>"
+				if e.val.value.HasValue() {
+					val = e.val.value.String()
+				}
+				got = append(got, [2]string{Key{e.val.section, e.val.name}.String(), val})
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("ParseEnv() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseEnv_RoundTrip(t *testing.T) {
+	gc, err := Parse([]byte("[user]\n\tname = \"it's John!\"\n[remote \"origin\"]\n\turl = a\n\turl = \" b \"\n[core]\n\tbare\n\teditor =\n"))
+	if err != nil {
+		t.Fatalf("Parse() error = %v, want %v", err, nil)
+	}
+
+	fromParams, err := ParseEnv([]string{"GIT_CONFIG_PARAMETERS=" + gc.EncodeParameters()})
+	if err != nil {
+		t.Fatalf("ParseEnv() error = %v, want %v", err, nil)
+	}
+	if got, want := listNull(fromParams), listNull(gc); got != want {
+		t.Errorf("ParseEnv() of EncodeParameters() = %q, want %q", got, want)
+	}
+
+	fromCount, err := ParseEnv(gc.Environ())
+	if err != nil {
+		t.Fatalf("ParseEnv() error = %v, want %v", err, nil)
+	}
+	want := listNull(gc)
+	want = want[:len(want)-len("core.bare\x00core.editor\n\x00")] + "core.bare\ntrue\x00core.editor\n\x00"
+	if got := listNull(fromCount); got != want {
+		t.Errorf("ParseEnv() of Environ() = %q, want %q", got, want)
+	}
+}
+
+func TestParseEnv_Git(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping comparison with git in short mode")
+	}
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found")
+	}
+
+	gc, err := Parse([]byte("[user]\n\tname = \"it's John!\"\n[a \"b.c\"]\n\td = \"x\\ty\"\n[core]\n\tbare\n\teditor =\n"))
+	if err != nil {
+		t.Fatalf("Parse() error = %v, want %v", err, nil)
+	}
+
+	environ := append(gc.Environ(), "GIT_CONFIG_PARAMETERS="+gc.EncodeParameters())
+	cmd := exec.Command("git", "config", "--list", "--null")
+	cmd.Env = append(os.Environ(), "GIT_CONFIG_NOSYSTEM=1", "GIT_CONFIG_GLOBAL=/dev/null")
+	cmd.Env = append(cmd.Env, environ...)
+	cmd.Dir = t.TempDir()
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("git config --list error = %v", err)
+	}
+
+	want, err := ParseEnv(environ)
+	if err != nil {
+		t.Fatalf("ParseEnv() error = %v, want %v", err, nil)
+	}
+	if got := string(out); got != listNull(want) {
+		t.Errorf("git config --list = %q, want %q", got, listNull(want))
+	}
+}
@@ -3,11 +3,13 @@ package gitconfig
 import (
 	"errors"
 	"fmt"
+	"strings"
 )
 
 var (
 	ErrInvalidKey           = errors.New("invalid key format")
 	ErrKeyNotFound          = errors.New("could not find the given key")
+	ErrSectionNotFound      = errors.New("could not find the given section")
 	ErrInvalidValueType     = errors.New("invalid value type")
 	ErrEmptyValue           = errors.New("empty value")
 	ErrInvalidSection       = errors.New("illegal characters in section")
@@ -15,15 +17,181 @@ var (
 	ErrInvalidVariableName  = errors.New("illegal characters in variable name")
 	ErrInvalidVariableValue = errors.New("illegal characters in variable value")
 	ErrInvalidLine          = errors.New("illegal characters in line")
+	ErrIncludeCycle         = errors.New("circular include")
+	ErrIncludeDepth         = errors.New("exceeded maximum include depth")
+	ErrRelativeCondition    = errors.New("relative include conditions must come from files")
+	ErrInvalidBool          = errors.New("bad boolean config value")
+	ErrInvalidNumber        = errors.New("bad numeric config value")
+	ErrInvalidColor         = errors.New("bad color config value")
+	ErrInvalidDate          = errors.New("bad date config value")
+	ErrConfigLocked         = errors.New("lock file already exists")
+	ErrInvalidPattern       = errors.New("invalid pattern")
+	ErrUnknownKey           = errors.New("unknown key")
+	ErrInvalidEnum          = errors.New("bad config value")
+	ErrInvalidEnv           = errors.New("bogus config environment")
+	ErrInvalidURL           = errors.New("invalid URL")
+	ErrFormatChangesValues  = errors.New("formatting would change the values of the config")
 )
 
+// ParseErrorCode identifies the kind of a ParseError.
+type ParseErrorCode int
+
+const (
+	_ ParseErrorCode = iota
+	CodeInvalidLine
+	CodeInvalidSection
+	CodeInvalidSubsection
+	CodeInvalidVariableName
+	CodeInvalidVariableValue
+)
+
+var parseErrorCodeString = []string{
+	"unknown",
+	"invalid-line",
+	"invalid-section",
+	"invalid-subsection",
+	"invalid-variable-name",
+	"invalid-variable-value",
+}
+
+func (pc ParseErrorCode) String() string {
+	if pc < 0 || int(pc) >= len(parseErrorCodeString) {
+		return parseErrorCodeString[0]
+	}
+	return parseErrorCodeString[pc]
+}
+
+// parseErrorCode returns the code of the parse error err.
+func parseErrorCode(err error) ParseErrorCode {
+	switch {
+	case errors.Is(err, ErrInvalidLine):
+		return CodeInvalidLine
+	case errors.Is(err, ErrInvalidSection):
+		return CodeInvalidSection
+	case errors.Is(err, ErrInvalidSubsection):
+		return CodeInvalidSubsection
+	case errors.Is(err, ErrInvalidVariableName):
+		return CodeInvalidVariableName
+	case errors.Is(err, ErrInvalidVariableValue):
+		return CodeInvalidVariableValue
+	}
+	return 0
+}
+
 // ParseError returned if there's an error while parsing
 type ParseError struct {
 	Err        error
+	Code       ParseErrorCode
 	Line       string
 	LineNumber int
+	Column     int // byte offset of the error in the line, starting at 1
+	Offset     int // byte offset of the error in the file, starting at 0
 }
 
 func (pe *ParseError) Error() string {
-	return fmt.Sprintf("%s: %s (line %d)", pe.Err.Error(), pe.Line, pe.LineNumber)
+	return fmt.Sprintf("%s: %s (line %d, column %d)", pe.Err.Error(), pe.Line, pe.LineNumber, pe.Column)
+}
+
+func (pe *ParseError) Unwrap() error {
+	return pe.Err
+}
+
+// ParseErrors returned by ParseWith() in lenient mode, with every error found, in order.
+type ParseErrors []*ParseError
+
+func (pe ParseErrors) Error() string {
+	msgs := make([]string, len(pe))
+	for i := range pe {
+		msgs[i] = pe[i].Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+func (pe ParseErrors) Unwrap() []error {
+	errs := make([]error, len(pe))
+	for i := range pe {
+		errs[i] = pe[i]
+	}
+	return errs
+}
+
+// IncludeError returned if an included file can't be loaded.
+type IncludeError struct {
+	Err  error
+	Path string
+}
+
+func (ie *IncludeError) Error() string {
+	return fmt.Sprintf("%s: %s", ie.Path, ie.Err.Error())
+}
+
+func (ie *IncludeError) Unwrap() error {
+	return ie.Err
+}
+
+// LockError returned if a config file can't be saved because it's locked.
+type LockError struct {
+	Err  error
+	Path string // path of the lock file
+}
+
+func (le *LockError) Error() string {
+	return fmt.Sprintf("unable to lock config file: %s: %s", le.Path, le.Err.Error())
+}
+
+func (le *LockError) Unwrap() error {
+	return le.Err
+}
+
+// FieldError returned if a struct field can't be encoded or decoded.
+type FieldError struct {
+	Err   error
+	Key   string
+	Field string // the struct field, as "<type>.<field>"
+}
+
+func (fe *FieldError) Error() string {
+	return fmt.Sprintf("%s (%s): %s", fe.Key, fe.Field, fe.Err.Error())
+}
+
+func (fe *FieldError) Unwrap() error {
+	return fe.Err
+}
+
+// ValidationError returned if a variable doesn't match its schema.
+type ValidationError struct {
+	Err        error
+	Key        string
+	Value      string
+	LineNumber int // 0 if the variable wasn't parsed
+}
+
+func (ve *ValidationError) Error() string {
+	if ve.LineNumber == 0 {
+		return fmt.Sprintf("%s: %s", ve.Key, ve.Err.Error())
+	}
+	return fmt.Sprintf("%s: %s (line %d)", ve.Key, ve.Err.Error(), ve.LineNumber)
+}
+
+func (ve *ValidationError) Unwrap() error {
+	return ve.Err
+}
+
+// ValidationErrors returned by Validate() with every variable that doesn't match its schema, in order.
+type ValidationErrors []*ValidationError
+
+func (ve ValidationErrors) Error() string {
+	msgs := make([]string, len(ve))
+	for i := range ve {
+		msgs[i] = ve[i].Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+func (ve ValidationErrors) Unwrap() []error {
+	errs := make([]error, len(ve))
+	for i := range ve {
+		errs[i] = ve[i]
+	}
+	return errs
 }
@@ -0,0 +1,185 @@
+package gitconfig
+
+import (
+	"slices"
+	"strings"
+)
+
+// FormatOptions controls how Format rewrites a config.
+type FormatOptions struct {
+	// Indent is written before every variable and every comment inside a
+	// section. If empty, a tab is used.
+	Indent string
+	// SortKeys sorts the variables of every section by name. The values of a
+	// multi-valued variable keep their order.
+	SortKeys bool
+}
+
+// FormatSource parses a config file and returns it formatted. See GitConfig.Format().
+// As a safety net, ErrFormatChangesValues is returned if the formatted config
+// wouldn't be read with the same values as in.
+func FormatSource(in []byte, opts FormatOptions) ([]byte, error) {
+	gc, err := Parse(in)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := gc.Format(opts).MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	formatted, err := Parse(out)
+	if err != nil || len(Diff(gc, formatted)) > 0 {
+		return nil, ErrFormatChangesValues
+	}
+
+	return out, nil
+}
+
+// Format returns a copy of g written the way git writes a config file:
+//   - section headers are written as `[section "subsection"]`, including
+//     the ones using the deprecated `[section.subsection]` syntax,
+//   - variables are indented and written as `name = value`, with values
+//     quoted and escaped like EncodeValue() does,
+//   - blank lines are removed and every block of a section is merged into
+//     the first one. Blocks of include and includeIf are never merged, and
+//     neither are blocks on different sides of them, as where they are in
+//     the file matters.
+//
+// Comments on their own lines stay right above the variable or section header
+// that follows them and comments following a header or a value stay on its line.
+// The values of every key are the same as in g.
+func (g GitConfig) Format(opts FormatOptions) *GitConfig {
+	if len(opts.Indent) == 0 {
+		opts.Indent = "\t"
+	}
+
+	f := formatter{opts: opts, eol: "\n"}
+	for e := g.lines.front(); e != nil; e = e.next {
+		if eol := lineEnding(e.val.raw); len(eol) > 0 {
+			f.eol = eol
+			break
+		}
+	}
+	f.read(g)
+	if opts.SortKeys {
+		for _, b := range f.blocks {
+			slices.SortStableFunc(b.entries, func(a, b formatEntry) int {
+				return strings.Compare(string(a.line.name.canonical()), string(b.line.name.canonical()))
+			})
+		}
+	}
+
+	return f.write()
+}
+
+type formatter struct {
+	opts   FormatOptions
+	eol    string
+	blocks []*formatBlock
+	end    []string // comments at the end of the file
+}
+
+// formatBlock is a section header with its variables.
+type formatBlock struct {
+	comments []string    // comments right above the header
+	header   *syntaxNode // nil for the variables before the first header
+	entries  []formatEntry
+}
+
+// formatEntry is a variable along with the comments right above it.
+type formatEntry struct {
+	comments []string
+	line     syntaxNode
+}
+
+// read splits the lines of g into blocks, merging the blocks of a section.
+func (f *formatter) read(g GitConfig) {
+	var (
+		comments []string
+		block    = new(formatBlock)
+		first    = make(map[Section]*formatBlock)
+	)
+	f.blocks = append(f.blocks, block)
+	for e := g.lines.front(); e != nil; e = e.next {
+		line := e.val
+		switch line.typ {
+		case comment:
+			comments = append(comments, strings.TrimSpace(line.raw))
+		case variable:
+			block.entries = append(block.entries, formatEntry{comments, line})
+			comments = nil
+		case section:
+			sec := line.section.canonical()
+			isInclude := sec.Name == "include" || sec.Name == "includeif"
+			if b, ok := first[sec]; ok && !isInclude {
+				// merged into b, the comments of the header go with the first variable of the block
+				block = b
+				if c := trailingComment(line); len(c) > 0 {
+					comments = append(comments, c)
+				}
+				continue
+			}
+			block = &formatBlock{comments: comments, header: &line}
+			comments = nil
+			if isInclude {
+				// moving variables across an include would change which value wins
+				clear(first)
+			}
+			first[sec] = block
+			f.blocks = append(f.blocks, block)
+		}
+	}
+	f.end = comments
+}
+
+// write returns the config made of the blocks.
+func (f *formatter) write() *GitConfig {
+	gc := New()
+	for _, b := range f.blocks {
+		indent := f.opts.Indent
+		if b.header == nil {
+			indent = ""
+		} else {
+			f.writeComments(gc, b.comments, "")
+			header := syntaxNode{typ: section, section: b.header.section}
+			header.raw = f.withComment(header.section.String(), trailingComment(*b.header))
+			gc.appendLine(header)
+		}
+
+		for _, entry := range b.entries {
+			f.writeComments(gc, entry.comments, indent)
+			line := newVariableLine(entry.line.section, entry.line.name, entry.line.value, indent)
+			line.raw = f.withComment(strings.TrimSuffix(line.raw, "\n"), trailingComment(entry.line))
+			gc.appendLine(line)
+		}
+	}
+	f.writeComments(gc, f.end, "")
+
+	return gc
+}
+
+func (f *formatter) writeComments(gc *GitConfig, comments []string, indent string) {
+	for _, c := range comments {
+		gc.appendLine(syntaxNode{typ: comment, raw: indent + c + f.eol})
+	}
+}
+
+// withComment returns the line s followed by comment c, if any.
+func (f *formatter) withComment(s, c string) string {
+	if len(c) > 0 {
+		s += " " + c
+	}
+	return s + f.eol
+}
+
+// trailingComment returns the comment following the header or the value of line, if any.
+func trailingComment(line syntaxNode) string {
+	c := new(configFile)
+	c.init([]byte(line.raw))
+	_, err := c.nextLine(line.section)
+	if err != nil || c.commentStart < 0 {
+		return ""
+	}
+	return string(c.data[c.commentStart:c.lineEnd])
+}
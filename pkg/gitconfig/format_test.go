@@ -0,0 +1,151 @@
+package gitconfig
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFormatSource(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		opts FormatOptions
+		want string
+	}{
+		{
+			name: "spacing",
+			in:   "[core]\n  bare=true\n\t\tautocrlf   =    input\n    editor = vim \\\n-u NONE\n\tmirror\n",
+			want: "[core]\n\tbare = true\n\tautocrlf = input\n\teditor = vim -u NONE\n\tmirror\n",
+		},
+		{
+			name: "indent",
+			in:   "[core]\n\tbare = true\n",
+			opts: FormatOptions{Indent: "    "},
+			want: "[core]\n    bare = true\n",
+		},
+		{
+			name: "quoting",
+			in:   "[user]\n\tname = \"John Doe\"\n\tpadded = \"  x  \"\n\tsemicolon = a\";\"b\n",
+			want: "[user]\n\tname = John Doe\n\tpadded = \"  x  \"\n\tsemicolon = \"a;b\"\n",
+		},
+		{
+			name: "headers",
+			in:   "  [Remote.Origin]\n\turl = a\n[branch \"Main\"]\n\tremote = origin\n",
+			want: "[Remote \"origin\"]\n\turl = a\n[branch \"Main\"]\n\tremote = origin\n",
+		},
+		{
+			name: "blank lines",
+			in:   "\n[core]\n\n\tbare = true\n\n[user]\n\tname = x\n\n",
+			want: "[core]\n\tbare = true\n[user]\n\tname = x\n",
+		},
+		{
+			name: "comments",
+			in:   "# top\n[core] ; header\n\t# above bare\n  bare = true # trailing\n; above user\n[user]\n\tname = \"a # b\"\n# end\n",
+			want: "# top\n[core] ; header\n\t# above bare\n\tbare = true # trailing\n; above user\n[user]\n\tname = \"a # b\"\n# end\n",
+		},
+		{
+			name: "variable after header",
+			in:   "[core] bare = true\n[user] name = x ; comment\n",
+			want: "[core]\n\tbare = true\n[user]\n\tname = x ; comment\n",
+		},
+		{
+			name: "merge duplicate blocks",
+			in:   "[core]\n\tbare = true\n[user]\n\tname = x\n[CORE] # again\n\t# above editor\n\teditor = vim\n\tbare = false\n",
+			want: "[core]\n\tbare = true\n\t# again\n\t# above editor\n\teditor = vim\n\tbare = false\n[user]\n\tname = x\n",
+		},
+		{
+			name: "includes aren't merged",
+			in:   "[include]\n\tpath = a\n[user]\n\tname = x\n[include]\n\tpath = b\n",
+			want: "[include]\n\tpath = a\n[user]\n\tname = x\n[include]\n\tpath = b\n",
+		},
+		{
+			name: "blocks across an include aren't merged",
+			in:   "[core]\n\teditor = vim\n[include]\n\tpath = inc\n[core]\n\teditor = nano\n[user]\n\tname = x\n[core]\n\tbare\n",
+			want: "[core]\n\teditor = vim\n[include]\n\tpath = inc\n[core]\n\teditor = nano\n\tbare\n[user]\n\tname = x\n",
+		},
+		{
+			name: "sort keys",
+			in:   "[remote \"origin\"]\n\turl = a\n\t# first fetch\n\tfetch = b\n\tFetch = c\n\tmirror\n",
+			opts: FormatOptions{SortKeys: true},
+			want: "[remote \"origin\"]\n\t# first fetch\n\tfetch = b\n\tFetch = c\n\tmirror\n\turl = a\n",
+		},
+		{
+			name: "crlf",
+			in:   "[core]\r\n  bare=true\r\n",
+			want: "[core]\r\n\tbare = true\r\n",
+		},
+		{
+			name: "empty section",
+			in:   "[core]\n[user]\n\tname = x",
+			want: "[core]\n[user]\n\tname = x\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FormatSource([]byte(tt.in), tt.opts)
+			if err != nil {
+				t.Fatalf("FormatSource() error = %v, want %v", err, nil)
+			}
+			if string(got) != tt.want {
+				t.Errorf("FormatSource() = %q, want %q", got, tt.want)
+			}
+
+			again, err := FormatSource(got, tt.opts)
+			if err != nil || string(again) != string(got) {
+				t.Errorf("FormatSource() of formatted config = (%q, %v), want (%q, %v)", again, err, got, nil)
+			}
+
+			before, _ := Parse([]byte(tt.in))
+			after, _ := Parse(got)
+			if changes := Diff(before, after); len(changes) > 0 {
+				t.Errorf("FormatSource() changed values:\n%s", changes)
+			}
+		})
+	}
+}
+
+func TestFormatSource_Error(t *testing.T) {
+	_, err := FormatSource([]byte("[core\n"), FormatOptions{})
+	if _, ok := err.(*ParseError); !ok {
+		t.Errorf("FormatSource() error = %v, want a *ParseError", err)
+	}
+}
+
+func TestGitConfig_Format_Include(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config")
+	writeConfigs(t, dir, map[string]string{
+		"config": "[core]\n\teditor = vim\n[include]\n\tpath = inc\n[core]\n\teditor = nano\n[includeIf \"gitdir:/nowhere/\"]\n\tpath = inc\n[core]\n\tpager = less\n",
+		"inc":    "[core]\n\teditor = emacs\n\tpager = more\n",
+	})
+
+	load := func() (editor, pager string) {
+		t.Helper()
+		gc, err := Load(path, LoadOptions{})
+		if err != nil {
+			t.Fatalf("Load() error = %v, want %v", err, nil)
+		}
+		e, _ := gc.Get("core.editor")
+		p, _ := gc.Get("core.pager")
+		return e.String(), p.String()
+	}
+	wantEditor, wantPager := load()
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	formatted, err := FormatSource(content, FormatOptions{SortKeys: true})
+	if err != nil {
+		t.Fatalf("FormatSource() error = %v, want %v", err, nil)
+	}
+	if err := os.WriteFile(path, formatted, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if editor, pager := load(); editor != wantEditor || pager != wantPager {
+		t.Errorf("Load() of formatted config = (%q, %q), want (%q, %q)", editor, pager, wantEditor, wantPager)
+	}
+}
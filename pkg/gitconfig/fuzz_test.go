@@ -0,0 +1,131 @@
+package gitconfig
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// addSamples seeds f with every config file of configsamples.
+func addSamples(f *testing.F) {
+	samples, err := filepath.Glob("configsamples/*.gitconfig")
+	if err != nil {
+		f.Fatalf("filepath.Glob() error = %v, want %v", err, nil)
+	}
+	for _, sample := range samples {
+		content, err := os.ReadFile(sample)
+		if err != nil {
+			f.Fatalf("os.ReadFile(%s) error = %v, want %v", sample, err, nil)
+		}
+		f.Add(content)
+	}
+}
+
+func FuzzParse(f *testing.F) {
+	addSamples(f)
+	f.Fuzz(func(t *testing.T, in []byte) {
+		gc, err := Parse(in)
+		if err != nil {
+			return
+		}
+		for _, key := range gc.Keys() {
+			// not GetAll(), a variable outside of any section has no valid key
+			_, err := gc.get(key.Section, key.Name)
+			if err != nil {
+				t.Errorf("GitConfig.get(%s) error = %v, want %v", key, err, nil)
+			}
+		}
+
+		lenient, err := ParseWith(in, ParseOptions{Lenient: true})
+		if err != nil {
+			t.Fatalf("ParseWith() error = %v, want %v", err, nil)
+		}
+		if listNull(lenient) != listNull(gc) {
+			t.Errorf("ParseWith() = %q, want %q", listNull(lenient), listNull(gc))
+		}
+	})
+}
+
+func FuzzParseLenient(f *testing.F) {
+	addSamples(f)
+	f.Fuzz(func(t *testing.T, in []byte) {
+		gc, _ := ParseWith(in, ParseOptions{Lenient: true})
+		text, err := gc.MarshalText()
+		if err != nil {
+			t.Fatalf("GitConfig.MarshalText() error = %v, want %v", err, nil)
+		}
+		// what's left once every invalid line is skipped is a valid config
+		_, err = Parse(text)
+		if err != nil {
+			t.Errorf("Parse(%q) error = %v, want %v", text, err, nil)
+		}
+	})
+}
+
+// FuzzMarshalParse checks the round trip in memory, Save is covered by TestGitConfig_SaveSamples.
+func FuzzMarshalParse(f *testing.F) {
+	addSamples(f)
+	f.Fuzz(func(t *testing.T, in []byte) {
+		gc, err := Parse(in)
+		if err != nil {
+			return
+		}
+
+		var buf bytes.Buffer
+		_, err = gc.WriteTo(&buf)
+		if err != nil {
+			t.Fatalf("GitConfig.WriteTo() error = %v, want %v", err, nil)
+		}
+		if !bytes.Equal(buf.Bytes(), in) {
+			t.Errorf("GitConfig.WriteTo() wrote %q, want %q", buf.Bytes(), in)
+		}
+		text, err := gc.MarshalText()
+		if err != nil || !bytes.Equal(text, in) {
+			t.Errorf("GitConfig.MarshalText() = (%q, %v), want (%q, %v)", text, err, in, nil)
+		}
+		got, err := Parse(text)
+		if err != nil {
+			t.Fatalf("Parse() error = %v, want %v", err, nil)
+		}
+		if listNull(got) != listNull(gc) {
+			t.Errorf("Parse() = %q, want %q", listNull(got), listNull(gc))
+		}
+	})
+}
+
+func FuzzValidateValue(f *testing.F) {
+	for _, s := range []string{"", "value", `a\"b`, `a\\b`, `a\nb`, "a \\\nb", `"quoted"`, `a\zb`, "a ; b", "\\"} {
+		f.Add(s)
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		_ = ValidateValue(s)
+
+		// any string can be stored once encoded
+		encoded := EncodeValue(s)
+		err := ValidateValue(encoded)
+		if err != nil {
+			t.Fatalf("ValidateValue(%q) error = %v, want %v", encoded, err, nil)
+		}
+		gc := New()
+		err = gc.Set("fuzz.value", encoded)
+		if err != nil {
+			t.Fatalf("GitConfig.Set() error = %v, want %v", err, nil)
+		}
+		text, err := gc.MarshalText()
+		if err != nil {
+			t.Fatalf("GitConfig.MarshalText() error = %v, want %v", err, nil)
+		}
+		got, err := Parse(text)
+		if err != nil {
+			t.Fatalf("Parse(%q) error = %v, want %v", text, err, nil)
+		}
+		val, err := got.Get("fuzz.value")
+		if err != nil {
+			t.Fatalf("GitConfig.Get() error = %v, want %v", err, nil)
+		}
+		if val.String() != s {
+			t.Errorf("Value.String() = %q, want %q", val.String(), s)
+		}
+	})
+}
@@ -1,8 +1,13 @@
 package gitconfig
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"io"
+	"io/fs"
 	"os"
+	"path/filepath"
 	"reflect"
 	"strings"
 )
@@ -10,12 +15,17 @@ import (
 // Section represents section name (and maybe subsection) of a config.
 type Section struct {
 	Name, Subsection string
+
+	// emptySubsection is set for an empty subsection, as in [remote ""],
+	// which git reads as a section of its own.
+	emptySubsection bool
 }
 
 // NewSection converts the given string to a Section.
 // Only alphanumeric characters and '-' are allowed for the section name.
 // To add a subsection, include a '.' after the section name.
 // Subsection names can contain any character except newline and null bytes.
+// A trailing '.' (e.g. "remote.") is an empty subsection, like [remote ""].
 // Example: "url.git@github.com" results in Name = "git", Subsection = "git@github.com".
 func NewSection(s string) (Section, error) {
 	var sec Section
@@ -23,6 +33,7 @@ func NewSection(s string) (Section, error) {
 	if ix > -1 {
 		sec.Name = s[:ix]
 		sec.Subsection = s[ix+1:]
+		sec.emptySubsection = len(sec.Subsection) == 0
 	} else {
 		sec.Name = s
 	}
@@ -36,21 +47,38 @@ func NewSection(s string) (Section, error) {
 	return sec, nil
 }
 
+// canonical returns s with its name lower-cased. Like git, section names
+// are case-insensitive while subsection names are case-sensitive.
+func (s Section) canonical() Section {
+	s.Name = strings.ToLower(s.Name)
+	return s
+}
+
+// subsectionEscaper escapes the characters git requires to be escaped in a
+// quoted subsection.
+var subsectionEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
+// String returns the section header of s, e.g. [remote "origin"].
 func (s Section) String() string {
-	if len(s.Subsection) == 0 {
+	if !s.hasSubsection() {
 		return fmt.Sprintf("[%s]", s.Name)
 	}
-	return fmt.Sprintf("[%s \"%s\"]", s.Name, s.Subsection)
+	return fmt.Sprintf("[%s \"%s\"]", s.Name, subsectionEscaper.Replace(s.Subsection))
 }
 
 // DottedString joins section name and subsection with a dot (.).
 func (s Section) DottedString() string {
-	if len(s.Subsection) == 0 {
+	if !s.hasSubsection() {
 		return s.Name
 	}
 	return s.Name + "." + s.Subsection
 }
 
+// hasSubsection reports whether s has a subsection, which may be empty.
+func (s Section) hasSubsection() bool {
+	return len(s.Subsection) > 0 || s.emptySubsection
+}
+
 func (s Section) isValidName() bool {
 	if len(s.Name) == 0 {
 		return false
@@ -69,6 +97,11 @@ func (s Section) isValidSubsection() bool {
 // VariableName represents config variable name.
 type VariableName string
 
+// canonical returns the lower-cased variable name, variable names are case-insensitive.
+func (vn VariableName) canonical() VariableName {
+	return VariableName(strings.ToLower(string(vn)))
+}
+
 func (vn VariableName) isValid() bool {
 	return len(vn) > 0 && isAlpha(vn[0]) && !strings.ContainsFunc(string(vn), func(r rune) bool {
 		return !(isAlnum(r) || r == '-')
@@ -89,15 +122,24 @@ func (k Key) String() string {
 // Value represents value of a config variable.
 type Value struct{ v interface{} }
 
+// Value returns the underlying value. It returns nil for a variable without
+// a value (e.g. "[core] bare").
 func (val Value) Value() interface{} {
 	return val.v
 }
 
+// HasValue reports whether the variable has a value. A variable without '='
+// (e.g. "[core] bare") has no value and is treated as true by Bool(), while
+// "bare =" has an empty value and is treated as false.
+func (val Value) HasValue() bool {
+	return val.v != nil
+}
+
 // ValidateValue validates whether s is a valid value for .gitconfig or not.
 // .gitconfig values can contain any characters and may span multiple lines.
 // '\' indicates that the config value continues on the next line. There MUST NOT be
 // any characters after '\'. If '\' is needed as part of the value, it MUST be
-// escaped to '\\'.
+// escaped to '\\'. The escape sequences '\"', '\n', '\t' and '\b' are also allowed.
 func ValidateValue(s string) error {
 	for i := 0; i < len(s); i++ {
 		if s[i] == '\\' {
@@ -105,7 +147,7 @@ func ValidateValue(s string) error {
 				break
 			}
 			switch s[i+1] {
-			case '\\', '"':
+			case '\\', '"', 'n', 't', 'b':
 				i++
 				continue
 			case '\n', '\t', '\b':
@@ -119,9 +161,56 @@ func ValidateValue(s string) error {
 	return nil
 }
 
+// EncodeValue converts s to the form git config uses to write it to a config file.
+// '\', '"', newlines and tabs are escaped, and the value is quoted if it has
+// leading or trailing spaces or contains comment characters (';' and '#').
+// Unlike git, values containing other whitespace characters that would be
+// collapsed when read ('\r', '\v', '\f') are quoted as well.
+// The result is a valid value for Set() and Add() whose String() is s.
+func EncodeValue(s string) string {
+	quote := strings.HasPrefix(s, " ") || strings.HasSuffix(s, " ") || strings.ContainsAny(s, ";#\r\v\f")
+
+	var sb strings.Builder
+	sb.Grow(len(s) + 2)
+	if quote {
+		sb.WriteByte('"')
+	}
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\n':
+			sb.WriteString(`\n`)
+		case '\t':
+			sb.WriteString(`\t`)
+		case '"', '\\':
+			sb.WriteByte('\\')
+			sb.WriteByte(s[i])
+		default:
+			sb.WriteByte(s[i])
+		}
+	}
+	if quote {
+		sb.WriteByte('"')
+	}
+
+	return sb.String()
+}
+
+// encode returns val the way it's written to a config file.
+func (val Value) encode() string {
+	if _, ok := val.v.(string); ok {
+		return EncodeValue(val.String())
+	}
+	return fmt.Sprintf("%v", val.v)
+}
+
+// String returns the value with quotes removed and escape sequences decoded.
+// It returns an empty string for a variable without a value.
 func (val Value) String() string {
 	var quoted bool
 
+	if val.v == nil {
+		return ""
+	}
 	s, ok := val.v.(string)
 	if !ok {
 		return fmt.Sprintf("%v", val.v)
@@ -152,6 +241,14 @@ func (val Value) String() string {
 				case 't':
 					ch = '\t'
 					i++
+				case '\n': // the value continues on the next line
+					i++
+					continue
+				case '\r':
+					if i < len(s)-2 && s[i+2] == '\n' {
+						i += 2
+						continue
+					}
 				}
 			}
 		}
@@ -161,11 +258,12 @@ func (val Value) String() string {
 	return string(b)
 }
 
-// TODO: maintain insertion order ?? how ??
-//
-//	add a slice to store the keys ??
+// GitConfig represents a git config file. Every line of the file (comments
+// and blank lines included) is kept in its original form, so a parsed config
+// can be saved back without losing anything.
 type GitConfig struct {
-	data *orderedMap[Section, *orderedMap[VariableName, []Value]]
+	data  *orderedMap[Section, *orderedMap[VariableName, []*node[syntaxNode]]]
+	lines *list[syntaxNode]
 }
 
 func (GitConfig) isValidValues(vals ...interface{}) ([]Value, error) {
@@ -196,12 +294,12 @@ func (GitConfig) isValidValues(vals ...interface{}) ([]Value, error) {
 }
 
 func (g GitConfig) sectionExists(section Section) bool {
-	_, ok := g.data.get(section)
+	_, ok := g.data.get(section.canonical())
 	return ok
 }
 
 func (g GitConfig) keyExists(section Section, key VariableName) bool {
-	_, ok := g.data.mustGet(section).get(key)
+	_, ok := g.data.mustGet(section.canonical()).get(key.canonical())
 	return ok
 }
 
@@ -224,40 +322,141 @@ func (GitConfig) splitKey(k string) (Section, VariableName, error) {
 	return section, varName, nil
 }
 
+// nodes returns the variable lines of a given key, or nil if the key doesn't exist.
+func (g GitConfig) nodes(section Section, key VariableName) []*node[syntaxNode] {
+	if !g.sectionExists(section) || !g.keyExists(section, key) {
+		return nil
+	}
+	return g.data.mustGet(section.canonical()).mustGet(key.canonical())
+}
+
 func (g GitConfig) get(section Section, key VariableName) ([]Value, error) {
-	if !g.sectionExists(section) {
+	nodes := g.nodes(section, key)
+	if len(nodes) == 0 {
 		return nil, ErrKeyNotFound
 	}
 
-	if !g.keyExists(section, key) {
-		return nil, ErrKeyNotFound
+	values := make([]Value, 0, len(nodes))
+	for i := range nodes {
+		values = append(values, nodes[i].val.value)
 	}
 
-	return g.data.mustGet(section).mustGet(key), nil
+	return values, nil
 }
 
-func (g *GitConfig) add(section Section, name VariableName, vals ...Value) {
-	if !g.sectionExists(section) {
-		g.data.put(section, newOrderedMap[VariableName, []Value]())
+// appendLine adds a parsed line to the end of the config.
+func (g *GitConfig) appendLine(line syntaxNode) {
+	e := g.lines.pushBack(line)
+	if line.typ == variable {
+		g.index(e)
 	}
+}
 
-	if !g.keyExists(section, name) {
-		g.data.mustGet(section).put(name, make([]Value, 0))
+// insertLine inserts line right after at. If at is nil, line becomes
+// the first line of the config.
+func (g *GitConfig) insertLine(at *node[syntaxNode], line syntaxNode) *node[syntaxNode] {
+	if at != nil && !strings.HasSuffix(at.val.raw, "\n") {
+		at.val.raw += "\n"
+	}
+	return g.lines.insertAfter(at, line)
+}
+
+// removeLine removes line e. If e follows a section header on the same line,
+// its line terminator is kept on the header.
+func (g *GitConfig) removeLine(e *node[syntaxNode]) {
+	if e.prev != nil && !strings.HasSuffix(e.prev.val.raw, "\n") {
+		e.prev.val.raw += lineEnding(e.val.raw)
+	}
+	g.lines.remove(e)
+}
+
+// appendVariable adds variable line v to the end of the config, preceded by a
+// header of its section if the current last line belongs to another section.
+func (g *GitConfig) appendVariable(v syntaxNode) {
+	if last := g.lines.back(); last == nil || last.val.section.canonical() != v.section.canonical() {
+		g.insertLine(last, newSectionLine(v.section))
+	}
+	g.index(g.insertLine(g.lines.back(), v))
+}
+
+// index makes variable line e reachable through its key.
+func (g *GitConfig) index(e *node[syntaxNode]) {
+	sec, name := e.val.section.canonical(), e.val.name.canonical()
+	if !g.sectionExists(sec) {
+		g.data.put(sec, newOrderedMap[VariableName, []*node[syntaxNode]]())
+	}
+
+	nodes, _ := g.data.mustGet(sec).get(name)
+	g.data.mustGet(sec).put(name, append(nodes, e))
+}
+
+// sectionEnd returns the last variable (or the header, if there's no variable)
+// of the last block of sec. It returns nil if sec doesn't exist.
+func (g GitConfig) sectionEnd(sec Section) *node[syntaxNode] {
+	var end *node[syntaxNode]
+	sec = sec.canonical()
+	for e := g.lines.front(); e != nil; e = e.next {
+		if (e.val.typ == section || e.val.typ == variable) && e.val.section.canonical() == sec {
+			end = e
+		}
+	}
+	return end
+}
+
+// insertVariable adds a new variable line at the end of section sec,
+// creating the section if it doesn't exist yet.
+func (g *GitConfig) insertVariable(sec Section, name VariableName, val Value) *node[syntaxNode] {
+	at := g.sectionEnd(sec)
+	if at == nil {
+		at = g.insertLine(g.lines.back(), newSectionLine(sec))
+	}
+	// spell the section the way the file already does
+	sec = at.val.section
+
+	indent := "\t"
+	if at.val.typ == variable {
+		indent = indentOf(at.val.raw)
 	}
 
-	g.data.mustGet(section).mustGetNode(name).val.val = append(g.data.mustGet(section).mustGet(name), vals...)
+	return g.insertLine(at, newVariableLine(sec, name, val, indent))
+}
+
+func (g *GitConfig) add(section Section, name VariableName, vals ...Value) {
+	for i := range vals {
+		g.index(g.insertVariable(section, name, vals[i]))
+	}
 }
 
 func (g *GitConfig) set(section Section, name VariableName, vals ...Value) {
-	if !g.sectionExists(section) {
-		g.data.put(section, newOrderedMap[VariableName, []Value]())
+	var nodes []*node[syntaxNode]
+	if g.sectionExists(section) {
+		nodes, _ = g.data.mustGet(section.canonical()).get(name.canonical())
 	}
 
-	if !g.keyExists(section, name) {
-		g.data.mustGet(section).put(name, make([]Value, 0))
+	if len(nodes) == 0 {
+		g.add(section, name, vals...)
+		return
+	}
+
+	// reuse the existing lines so unchanged values keep their original form
+	updated := make([]*node[syntaxNode], 0, len(vals))
+	for i := range vals {
+		if i < len(nodes) {
+			if nodes[i].val.value != vals[i] {
+				nodes[i].val.setValue(vals[i])
+			}
+			updated = append(updated, nodes[i])
+			continue
+		}
+		prev := updated[i-1]
+		updated = append(updated, g.insertLine(prev, newVariableLine(prev.val.section, prev.val.name, vals[i], indentOf(prev.val.raw))))
 	}
 
-	g.data.mustGet(section).put(name, vals)
+	for i := len(updated); i < len(nodes); i++ {
+		g.removeLine(nodes[i])
+	}
+
+	g.data.mustGet(section.canonical()).put(name.canonical(), updated)
 }
 
 func (g *GitConfig) unset(section Section, name VariableName) error {
@@ -269,10 +468,16 @@ func (g *GitConfig) unset(section Section, name VariableName) error {
 		return ErrKeyNotFound
 	}
 
-	if g.data.mustGet(section).len() == 1 {
-		g.data.remove(section)
+	sec, key := section.canonical(), name.canonical()
+	for _, e := range g.data.mustGet(sec).mustGet(key) {
+		g.removeLine(e)
+	}
+
+	// like git, the section header is kept even if the section is now empty
+	if g.data.mustGet(sec).len() == 1 {
+		g.data.remove(sec)
 	} else {
-		g.data.mustGet(section).remove(name)
+		g.data.mustGet(sec).remove(key)
 	}
 
 	return nil
@@ -281,7 +486,8 @@ func (g *GitConfig) unset(section Section, name VariableName) error {
 // New creates a new GitConfig.
 func New() *GitConfig {
 	return &GitConfig{
-		data: newOrderedMap[Section, *orderedMap[VariableName, []Value]](),
+		data:  newOrderedMap[Section, *orderedMap[VariableName, []*node[syntaxNode]]](),
+		lines: new(list[syntaxNode]),
 	}
 }
 
@@ -322,6 +528,8 @@ func (g GitConfig) GetAll(key string) ([]Value, error) {
 
 // Set assigns vals to a given key. If the key already exists, the current value is
 // replaced. To add new values to an existing key, use Add().
+// String values are written in config file syntax (see ValidateValue), to store a
+// string as is, encode it with EncodeValue() first.
 func (g *GitConfig) Set(key string, vals ...interface{}) error {
 	if len(vals) == 0 {
 		return ErrEmptyValue
@@ -363,9 +571,8 @@ func (g *GitConfig) Add(key string, vals ...interface{}) error {
 	return nil
 }
 
-// Unset removes given key from config file. If the section contains only one variable,
-// the section is removed, if it contains more than one variables,
-// only variable with the given key is removed.
+// Unset removes given key from config file. Like git, the section is kept even
+// if the key was its last variable, use RemoveSection() to remove it.
 func (g *GitConfig) Unset(key string) error {
 	section, varName, err := g.splitKey(key)
 	if err != nil {
@@ -382,36 +589,100 @@ func (g *GitConfig) Unset(key string) error {
 
 // Save writes the current configuration to path.
 // If the file already exists, it will be overwritten.
-func (g GitConfig) Save(path string) error {
-	f, err := os.Create(path)
+// Lines that haven't been modified are written exactly as they were parsed.
+//
+// Like git, the configuration is first written to "<path>.lock", which is then
+// renamed to path, so the file is never left half-written. If the lock file
+// already exists (e.g. git is writing the same file), Save fails with a *LockError.
+// The mode of an existing file is preserved and if path is a symbolic link,
+// the file it points to is replaced.
+func (g GitConfig) Save(path string) (err error) {
+	var exists bool
+	mode := fs.FileMode(0o666)
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	}
+	if info, err := os.Stat(path); err == nil {
+		exists, mode = true, info.Mode().Perm()
+	}
+
+	lockPath := path + ".lock"
+	f, err := os.OpenFile(lockPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, mode)
 	if err != nil {
+		if errors.Is(err, fs.ErrExist) {
+			return &LockError{Err: ErrConfigLocked, Path: lockPath}
+		}
 		return err
 	}
-	defer f.Close()
+	defer func() {
+		if err != nil {
+			f.Close()
+			os.Remove(lockPath)
+		}
+	}()
 
-	sections := g.data.keys()
-	for i := range sections {
-		_, err = fmt.Fprintf(f, "%s\n", sections[i])
+	if exists {
+		// the mode given to OpenFile is affected by umask
+		err = f.Chmod(mode)
 		if err != nil {
 			return err
 		}
-		variables := g.data.mustGet(sections[i]).keys()
-		for j := range variables {
-			values := g.data.mustGet(sections[i]).mustGet(variables[j])
-			for k := range values {
-				_, err = fmt.Fprintf(f, "\t%s = %v\n", variables[j], values[k].Value())
-				if err != nil {
-					return err
-				}
-			}
+	}
+	_, err = g.WriteTo(f)
+	if err != nil {
+		return err
+	}
+	err = f.Sync()
+	if err != nil {
+		return err
+	}
+	err = f.Close()
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(lockPath, path)
+}
+
+// WriteTo writes the current configuration to w. It implements io.WriterTo.
+func (g GitConfig) WriteTo(w io.Writer) (int64, error) {
+	var total int64
+	for e := g.lines.front(); e != nil; e = e.next {
+		n, err := io.WriteString(w, e.val.raw)
+		total += int64(n)
+		if err != nil {
+			return total, err
 		}
 	}
 
+	return total, nil
+}
+
+// MarshalText returns the content of the config file. It implements encoding.TextMarshaler.
+func (g GitConfig) MarshalText() ([]byte, error) {
+	var buf bytes.Buffer
+	_, err := g.WriteTo(&buf)
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// UnmarshalText parses text and replaces g with the result.
+// It implements encoding.TextUnmarshaler.
+func (g *GitConfig) UnmarshalText(text []byte) error {
+	gc, err := Parse(text)
+	if err != nil {
+		return err
+	}
+	*g = *gc
+
 	return nil
 }
 
 // Keys returns slice of all keys in the order they're
-// inserted.
+// inserted. Keys are spelled the way they first appear in the config.
 func (g GitConfig) Keys() []Key {
 	keys := make([]Key, 0)
 
@@ -422,7 +693,8 @@ func (g GitConfig) Keys() []Key {
 		}
 
 		for _, name := range variables.keys() {
-			keys = append(keys, Key{section, name})
+			first := variables.mustGet(name)[0].val
+			keys = append(keys, Key{first.section, first.name})
 		}
 	}
 
@@ -1,10 +1,15 @@
 package gitconfig
 
 import (
+	"bytes"
 	"errors"
+	"io/fs"
+	"math/rand"
 	"os"
+	"path/filepath"
 	"reflect"
 	"testing"
+	"testing/iotest"
 )
 
 var (
@@ -589,3 +594,504 @@ func TestKeys(t *testing.T) {
 		}
 	}
 }
+
+func TestGitConfig_SaveUnchanged(t *testing.T) {
+	tests := []struct {
+		name       string
+		configPath string
+	}{
+		{
+			name:       "good config",
+			configPath: "configsamples/good.gitconfig",
+		},
+		{
+			name:       "config with comments",
+			configPath: "configsamples/comments.gitconfig",
+		},
+		{
+			name:       "config with CRLF and no trailing newline",
+			configPath: "configsamples/crlf.gitconfig",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want, err := os.ReadFile(tt.configPath)
+			if err != nil {
+				t.Fatalf("os.ReadFile(%s) error = %v, want %v", tt.configPath, err, nil)
+			}
+			gc, err := Parse(want)
+			if err != nil {
+				t.Fatalf("Parse() error = %v, want %v", err, nil)
+			}
+			filePath := filepath.Join(t.TempDir(), "config")
+			err = gc.Save(filePath)
+			if err != nil {
+				t.Fatalf("GitConfig.Save() error = %v, want %v", err, nil)
+			}
+			got, err := os.ReadFile(filePath)
+			if err != nil {
+				t.Fatalf("os.ReadFile(%s) error = %v, want %v", filePath, err, nil)
+			}
+			if string(got) != string(want) {
+				t.Errorf("GitConfig.Save() wrote\n%q\nwant\n%q", got, want)
+			}
+		})
+	}
+}
+
+func TestGitConfig_SaveModified(t *testing.T) {
+	const config = "# global config\n" +
+		"[user]\n" +
+		"    name = John Doe # my name\n" +
+		"    email = john@example.com\n" +
+		"\n" +
+		"[core]\n" +
+		"\teditor = vim\n" +
+		"# the end\n"
+
+	tests := []struct {
+		name   string
+		modify func(g *GitConfig) error
+		want   string
+	}{
+		{
+			name: "Set Same Value",
+			modify: func(g *GitConfig) error {
+				return g.Set("user.name", "John Doe")
+			},
+			want: config,
+		},
+		{
+			name: "Set Existing Key",
+			modify: func(g *GitConfig) error {
+				return g.Set("user.email", "doe@example.com")
+			},
+			want: "# global config\n" +
+				"[user]\n" +
+				"    name = John Doe # my name\n" +
+				"    email = doe@example.com\n" +
+				"\n" +
+				"[core]\n" +
+				"\teditor = vim\n" +
+				"# the end\n",
+		},
+		{
+			name: "Set Key With Comment",
+			modify: func(g *GitConfig) error {
+				return g.Set("user.name", "Jane Doe")
+			},
+			want: "# global config\n" +
+				"[user]\n" +
+				"    name = Jane Doe # my name\n" +
+				"    email = john@example.com\n" +
+				"\n" +
+				"[core]\n" +
+				"\teditor = vim\n" +
+				"# the end\n",
+		},
+		{
+			name: "Add To Existing Section",
+			modify: func(g *GitConfig) error {
+				return g.Add("user.signingKey", "ABCDEF")
+			},
+			want: "# global config\n" +
+				"[user]\n" +
+				"    name = John Doe # my name\n" +
+				"    email = john@example.com\n" +
+				"    signingKey = ABCDEF\n" +
+				"\n" +
+				"[core]\n" +
+				"\teditor = vim\n" +
+				"# the end\n",
+		},
+		{
+			name: "Add To New Section",
+			modify: func(g *GitConfig) error {
+				return g.Add("commit.gpgsign", true)
+			},
+			want: config + "[commit]\n\tgpgsign = true\n",
+		},
+		{
+			name: "Add To Subsection With Quote And Backslash",
+			modify: func(g *GitConfig) error {
+				return g.Add(`remote.a"b\c.url`, "x")
+			},
+			want: config + "[remote \"a\\\"b\\\\c\"]\n\turl = x\n",
+		},
+		{
+			name: "Unset Key",
+			modify: func(g *GitConfig) error {
+				return g.Unset("user.name")
+			},
+			want: "# global config\n" +
+				"[user]\n" +
+				"    email = john@example.com\n" +
+				"\n" +
+				"[core]\n" +
+				"\teditor = vim\n" +
+				"# the end\n",
+		},
+		{
+			name: "Unset Last Key In Section",
+			modify: func(g *GitConfig) error {
+				return g.Unset("core.editor")
+			},
+			want: "# global config\n" +
+				"[user]\n" +
+				"    name = John Doe # my name\n" +
+				"    email = john@example.com\n" +
+				"\n" +
+				"[core]\n" +
+				"# the end\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gc, err := Parse([]byte(config))
+			if err != nil {
+				t.Fatalf("Parse() error = %v, want %v", err, nil)
+			}
+			err = tt.modify(gc)
+			if err != nil {
+				t.Fatalf("modify() error = %v, want %v", err, nil)
+			}
+			filePath := filepath.Join(t.TempDir(), "config")
+			err = gc.Save(filePath)
+			if err != nil {
+				t.Fatalf("GitConfig.Save() error = %v, want %v", err, nil)
+			}
+			got, err := os.ReadFile(filePath)
+			if err != nil {
+				t.Fatalf("os.ReadFile(%s) error = %v, want %v", filePath, err, nil)
+			}
+			if string(got) != tt.want {
+				t.Errorf("GitConfig.Save() wrote\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGitConfig_CaseInsensitive(t *testing.T) {
+	const config = "[User]\n" +
+		"\tsigningkey = ABCDEF\n" +
+		"[url \"Git@Example.com:\"]\n" +
+		"\tinsteadOf = https://example.com/\n" +
+		"[Core.Legacy]\n" +
+		"\tfoo = bar\n"
+
+	gc, err := Parse([]byte(config))
+	if err != nil {
+		t.Fatalf("Parse() error = %v, want %v", err, nil)
+	}
+
+	tests := []struct {
+		name    string
+		key     string
+		want    Value
+		wantErr error
+	}{
+		{
+			name: "Different Case In Section And Variable Name",
+			key:  "user.signingKey",
+			want: Value{"ABCDEF"},
+		},
+		{
+			name: "Different Case In Section Name Only",
+			key:  "URL.Git@Example.com:.INSTEADOF",
+			want: Value{"https://example.com/"},
+		},
+		{
+			name:    "Different Case In Subsection",
+			key:     "url.git@example.com:.insteadOf",
+			wantErr: ErrKeyNotFound,
+		},
+		{
+			name: "Legacy Subsection Is Case Insensitive",
+			key:  "core.legacy.foo",
+			want: Value{"bar"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := gc.Get(tt.key)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("GitConfig.Get(%s) error = %v, wantErr %v", tt.key, err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GitConfig.Get(%s) = %v, want %v", tt.key, got, tt.want)
+			}
+		})
+	}
+
+	err = gc.Set("user.signingKey", "123456")
+	if err != nil {
+		t.Fatalf("GitConfig.Set() error = %v, want %v", err, nil)
+	}
+	err = gc.Add("USER.name", "John Doe")
+	if err != nil {
+		t.Fatalf("GitConfig.Add() error = %v, want %v", err, nil)
+	}
+
+	wantKeys := []string{"User.signingkey", "User.name", "url.Git@Example.com:.insteadOf", "Core.legacy.foo"}
+	keys := gc.Keys()
+	if len(keys) != len(wantKeys) {
+		t.Fatalf("len(keys) = %d, want %d", len(keys), len(wantKeys))
+	}
+	for i := range keys {
+		if keys[i].String() != wantKeys[i] {
+			t.Errorf("keys[%d] = %s, want %s", i, keys[i], wantKeys[i])
+		}
+	}
+
+	filePath := filepath.Join(t.TempDir(), "config")
+	err = gc.Save(filePath)
+	if err != nil {
+		t.Fatalf("GitConfig.Save() error = %v, want %v", err, nil)
+	}
+	got, err := os.ReadFile(filePath)
+	if err != nil {
+		t.Fatalf("os.ReadFile(%s) error = %v, want %v", filePath, err, nil)
+	}
+	want := "[User]\n" +
+		"\tsigningkey = 123456\n" +
+		"\tname = John Doe\n" +
+		"[url \"Git@Example.com:\"]\n" +
+		"\tinsteadOf = https://example.com/\n" +
+		"[Core.Legacy]\n" +
+		"\tfoo = bar\n"
+	if string(got) != want {
+		t.Errorf("GitConfig.Save() wrote\n%q\nwant\n%q", got, want)
+	}
+}
+
+func TestEncodeValue(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		want string
+	}{
+		{name: "plain", s: "plain", want: "plain"},
+		{name: "empty", s: "", want: ""},
+		{name: "leading space", s: " lead", want: `" lead"`},
+		{name: "trailing space", s: "trail ", want: `"trail "`},
+		{name: "inner spaces", s: "in  side", want: "in  side"},
+		{name: "hash", s: "a#b", want: `"a#b"`},
+		{name: "semicolon", s: "a;b", want: `"a;b"`},
+		{name: "tab", s: "tab\there", want: `tab\there`},
+		{name: "newline", s: "new\nline", want: `new\nline`},
+		{name: "double quote", s: `q"uote`, want: `q\"uote`},
+		{name: "backslash", s: `C:\Users\foo`, want: `C:\\Users\\foo`},
+		{name: "carriage return", s: "cr\rx", want: "\"cr\rx\""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := EncodeValue(tt.s)
+			if got != tt.want {
+				t.Errorf("EncodeValue(%q) = %q, want %q", tt.s, got, tt.want)
+			}
+			if err := ValidateValue(got); err != nil {
+				t.Errorf("ValidateValue(%q) = %v, want %v", got, err, nil)
+			}
+			if s := (Value{got}).String(); s != tt.s {
+				t.Errorf("Value{%q}.String() = %q, want %q", got, s, tt.s)
+			}
+		})
+	}
+}
+
+func TestGitConfig_SaveRoundTrip(t *testing.T) {
+	const alphabet = " \t\n\r\v\f\b\\\"';#=[]{}.-_aZ9é☁"
+	chars := []rune(alphabet)
+	rng := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 1000; i++ {
+		runes := make([]rune, rng.Intn(24))
+		for j := range runes {
+			runes[j] = chars[rng.Intn(len(chars))]
+		}
+		want := string(runes)
+
+		gc := New()
+		err := gc.Set("foo.bar", EncodeValue(want))
+		if err != nil {
+			t.Fatalf("GitConfig.Set(%q) error = %v, want %v", want, err, nil)
+		}
+		content, err := gc.MarshalText()
+		if err != nil {
+			t.Fatalf("GitConfig.MarshalText() error = %v, want %v", err, nil)
+		}
+		parsed, err := Parse(content)
+		if err != nil {
+			t.Fatalf("Parse(%q) error = %v, want %v", content, err, nil)
+		}
+		got, err := parsed.Get("foo.bar")
+		if err != nil || got.String() != want {
+			t.Fatalf("Parse(%q).Get() = (%q, %v), want (%q, %v)", content, got.String(), err, want, nil)
+		}
+	}
+}
+
+func TestGitConfig_ReaderWriter(t *testing.T) {
+	want, err := os.ReadFile("configsamples/comments.gitconfig")
+	if err != nil {
+		t.Fatalf("os.ReadFile error = %v, want %v", err, nil)
+	}
+
+	gc, err := ParseReader(bytes.NewReader(want))
+	if err != nil {
+		t.Fatalf("ParseReader() error = %v, want %v", err, nil)
+	}
+
+	var buf bytes.Buffer
+	n, err := gc.WriteTo(&buf)
+	if err != nil || n != int64(len(want)) {
+		t.Errorf("GitConfig.WriteTo() = (%d, %v), want (%d, %v)", n, err, len(want), nil)
+	}
+	if buf.String() != string(want) {
+		t.Errorf("GitConfig.WriteTo() wrote\n%q\nwant\n%q", buf.String(), want)
+	}
+
+	text, err := gc.MarshalText()
+	if err != nil || string(text) != string(want) {
+		t.Errorf("GitConfig.MarshalText() = (%q, %v), want (%q, %v)", text, err, want, nil)
+	}
+
+	unmarshaled := New()
+	err = unmarshaled.UnmarshalText(text)
+	if err != nil {
+		t.Fatalf("GitConfig.UnmarshalText() error = %v, want %v", err, nil)
+	}
+	got, err := unmarshaled.Get("core.autocrlf")
+	if err != nil || got.String() != "input" {
+		t.Errorf("GitConfig.Get() = (%v, %v), want (%v, %v)", got, err, "input", nil)
+	}
+
+	err = unmarshaled.UnmarshalText([]byte("[foo\n"))
+	if err == nil {
+		t.Errorf("GitConfig.UnmarshalText() error = %v, want a ParseError", err)
+	}
+
+	readErr := errors.New("read error")
+	_, err = ParseReader(iotest.ErrReader(readErr))
+	if !errors.Is(err, readErr) {
+		t.Errorf("ParseReader() error = %v, want %v", err, readErr)
+	}
+}
+
+func TestGitConfig_SaveSamples(t *testing.T) {
+	samples, err := filepath.Glob("configsamples/*.gitconfig")
+	if err != nil {
+		t.Fatalf("filepath.Glob() error = %v, want %v", err, nil)
+	}
+	dir := t.TempDir()
+	for _, sample := range samples {
+		t.Run(filepath.Base(sample), func(t *testing.T) {
+			want, err := os.ReadFile(sample)
+			if err != nil {
+				t.Fatalf("os.ReadFile(%s) error = %v, want %v", sample, err, nil)
+			}
+			gc, err := Parse(want)
+			if err != nil {
+				t.Skipf("Parse() error = %v", err)
+			}
+
+			path := filepath.Join(dir, filepath.Base(sample))
+			err = gc.Save(path)
+			if err != nil {
+				t.Fatalf("GitConfig.Save() error = %v, want %v", err, nil)
+			}
+			got, err := os.ReadFile(path)
+			if err != nil || !bytes.Equal(got, want) {
+				t.Errorf("GitConfig.Save() wrote (%q, %v), want (%q, %v)", got, err, want, nil)
+			}
+		})
+	}
+}
+
+func TestGitConfig_SaveLocked(t *testing.T) {
+	dir := t.TempDir()
+	filePath := filepath.Join(dir, "config")
+	const original = "[user]\n\tname = John Doe\n"
+	err := os.WriteFile(filePath, []byte(original), 0o600)
+	if err != nil {
+		t.Fatalf("os.WriteFile() error = %v, want %v", err, nil)
+	}
+
+	gc, err := Parse([]byte(original))
+	if err != nil {
+		t.Fatalf("Parse() error = %v, want %v", err, nil)
+	}
+	err = gc.Set("user.name", "Jane Doe")
+	if err != nil {
+		t.Fatalf("GitConfig.Set() error = %v, want %v", err, nil)
+	}
+
+	lockPath := filePath + ".lock"
+	err = os.WriteFile(lockPath, nil, 0o600)
+	if err != nil {
+		t.Fatalf("os.WriteFile() error = %v, want %v", err, nil)
+	}
+
+	err = gc.Save(filePath)
+	lockErr := new(LockError)
+	if !errors.As(err, &lockErr) || !errors.Is(err, ErrConfigLocked) || lockErr.Path != lockPath {
+		t.Fatalf("GitConfig.Save() error = %v, want a LockError for %s", err, lockPath)
+	}
+	content, _ := os.ReadFile(filePath)
+	if string(content) != original {
+		t.Errorf("locked file content = %q, want %q", content, original)
+	}
+
+	err = os.Remove(lockPath)
+	if err != nil {
+		t.Fatalf("os.Remove() error = %v, want %v", err, nil)
+	}
+	err = gc.Save(filePath)
+	if err != nil {
+		t.Fatalf("GitConfig.Save() error = %v, want %v", err, nil)
+	}
+	if _, err := os.Stat(lockPath); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("os.Stat(%s) error = %v, want %v", lockPath, err, os.ErrNotExist)
+	}
+	info, err := os.Stat(filePath)
+	if err != nil || info.Mode().Perm() != 0o600 {
+		t.Errorf("saved file mode = %v, want %v", info.Mode().Perm(), fs.FileMode(0o600))
+	}
+	content, _ = os.ReadFile(filePath)
+	if want := "[user]\n\tname = Jane Doe\n"; string(content) != want {
+		t.Errorf("saved file content = %q, want %q", content, want)
+	}
+}
+
+func TestGitConfig_SaveSymlink(t *testing.T) {
+	dir := t.TempDir()
+	target := filepath.Join(dir, "dotfiles.gitconfig")
+	link := filepath.Join(dir, ".gitconfig")
+	err := os.WriteFile(target, []byte("[user]\n\tname = John Doe\n"), 0o644)
+	if err != nil {
+		t.Fatalf("os.WriteFile() error = %v, want %v", err, nil)
+	}
+	err = os.Symlink(target, link)
+	if err != nil {
+		t.Skipf("os.Symlink() error = %v", err)
+	}
+
+	gc := New()
+	err = gc.Set("user.name", "Jane Doe")
+	if err != nil {
+		t.Fatalf("GitConfig.Set() error = %v, want %v", err, nil)
+	}
+	err = gc.Save(link)
+	if err != nil {
+		t.Fatalf("GitConfig.Save() error = %v, want %v", err, nil)
+	}
+
+	info, err := os.Lstat(link)
+	if err != nil || info.Mode()&fs.ModeSymlink == 0 {
+		t.Errorf("os.Lstat(%s) = (%v, %v), want a symbolic link", link, info, err)
+	}
+	content, _ := os.ReadFile(target)
+	if want := "[user]\n\tname = Jane Doe\n"; string(content) != want {
+		t.Errorf("symlink target content = %q, want %q", content, want)
+	}
+}
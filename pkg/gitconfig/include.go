@@ -0,0 +1,166 @@
+package gitconfig
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// DefaultMaxIncludeDepth is the maximum nesting of included files, the same limit git uses.
+const DefaultMaxIncludeDepth = 10
+
+// ConditionFunc reports whether includeIf.<condition>.path found in the
+// config file at path should be followed.
+type ConditionFunc func(condition, path string) (bool, error)
+
+// LoadOptions controls how Load resolves included files.
+type LoadOptions struct {
+	// MaxDepth limits how deeply included files can be nested.
+	// If zero, DefaultMaxIncludeDepth is used.
+	MaxDepth int
+	// IncludeIf decides which conditional includes are followed.
+	// If nil, every includeIf.<condition>.path is ignored.
+	IncludeIf ConditionFunc
+}
+
+// Load reads the config file at path and follows its include.path and
+// includeIf.<condition>.path variables, recursively. The returned GitConfig is a
+// merged view where the variables of an included file appear right after the
+// variable that includes it, so (like git) a later value overrides an earlier one.
+//
+// Relative include paths are resolved from the directory of the including file
+// and a leading "~/" is expanded to the user's home directory. Included files
+// that don't exist are ignored.
+func Load(path string, opts LoadOptions) (*GitConfig, error) {
+	if opts.MaxDepth == 0 {
+		opts.MaxDepth = DefaultMaxIncludeDepth
+	}
+
+	l := loader{
+		opts:   opts,
+		merged: New(),
+	}
+	err := l.load(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return l.merged, nil
+}
+
+type loader struct {
+	opts   LoadOptions
+	stack  []string // absolute paths of the files currently being loaded
+	merged *GitConfig
+}
+
+func (l *loader) load(path string) error {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+	if slices.Contains(l.stack, abs) {
+		return &IncludeError{Path: path, Err: ErrIncludeCycle}
+	}
+	if len(l.stack) > l.opts.MaxDepth {
+		return &IncludeError{Path: path, Err: ErrIncludeDepth}
+	}
+
+	data, err := os.ReadFile(abs)
+	if err != nil {
+		return err
+	}
+	gc, err := Parse(data)
+	if err != nil {
+		return &IncludeError{Path: path, Err: err}
+	}
+
+	l.stack = append(l.stack, abs)
+	defer func() {
+		l.stack = l.stack[:len(l.stack)-1]
+	}()
+
+	for e := gc.lines.front(); e != nil; e = e.next {
+		if e.val.typ != variable {
+			continue
+		}
+		line := e.val
+		line.file = path
+		l.merged.appendVariable(line)
+
+		include, err := l.includePath(e.val, abs)
+		if err != nil {
+			return err
+		}
+		if len(include) == 0 {
+			continue
+		}
+		if _, err := os.Stat(include); errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		err = l.load(include)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// includePath returns the path of the file that should be included by
+// line, or an empty string if line isn't a (matching) include directive.
+func (l *loader) includePath(line syntaxNode, from string) (string, error) {
+	if line.name.canonical() != "path" {
+		return "", nil
+	}
+
+	sec := line.section.canonical()
+	switch {
+	case sec.Name == "include" && !sec.hasSubsection():
+	case sec.Name == "includeif" && len(sec.Subsection) > 0:
+		if l.opts.IncludeIf == nil {
+			return "", nil
+		}
+		ok, err := l.opts.IncludeIf(line.section.Subsection, from)
+		if err != nil || !ok {
+			return "", err
+		}
+	default:
+		return "", nil
+	}
+
+	path := line.value.String()
+	if len(path) == 0 {
+		return "", nil
+	}
+
+	return resolvePath(path, filepath.Dir(from))
+}
+
+// resolvePath expands a leading "~/" to the user's home directory and
+// makes a relative path relative to dir.
+func resolvePath(path, dir string) (string, error) {
+	path, err := expandHome(path)
+	if err != nil {
+		return "", err
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(dir, path)
+	}
+	return path, nil
+}
+
+func expandHome(path string) (string, error) {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	// not using filepath.Join, a trailing slash is meaningful in patterns
+	return strings.TrimRight(home, `/\`) + path[1:], nil
+}
@@ -0,0 +1,232 @@
+package gitconfig
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func writeConfigs(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		err := os.MkdirAll(filepath.Dir(path), 0o755)
+		if err != nil {
+			t.Fatalf("os.MkdirAll(%s) error = %v, want %v", filepath.Dir(path), err, nil)
+		}
+		err = os.WriteFile(path, []byte(content), 0o644)
+		if err != nil {
+			t.Fatalf("os.WriteFile(%s) error = %v, want %v", path, err, nil)
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	writeConfigs(t, home, map[string]string{
+		"profiles/work.gitconfig": "[user]\n\temail = work@example.com\n",
+	})
+
+	tests := []struct {
+		name    string
+		files   map[string]string
+		opts    LoadOptions
+		key     string
+		want    []Value
+		wantErr error
+	}{
+		{
+			name: "No Include",
+			files: map[string]string{
+				"config": "[user]\n\temail = me@example.com\n",
+			},
+			key:  "user.email",
+			want: []Value{{"me@example.com"}},
+		},
+		{
+			name: "Relative Include Overrides Earlier Value",
+			files: map[string]string{
+				"config":          "[user]\n\temail = me@example.com\n[include]\n\tpath = sub/extra.inc\n",
+				"sub/extra.inc":   "[user]\n\temail = extra@example.com\n[include]\n\tpath = nested.inc\n",
+				"sub/nested.inc":  "[user]\n\temail = nested@example.com\n",
+				"sub/ignored.inc": "[user]\n\temail = ignored@example.com\n",
+			},
+			key:  "user.email",
+			want: []Value{{"me@example.com"}, {"extra@example.com"}, {"nested@example.com"}},
+		},
+		{
+			name: "Later Value Overrides Include",
+			files: map[string]string{
+				"config": "[include]\n\tpath = ~/profiles/work.gitconfig\n[user]\n\temail = me@example.com\n",
+			},
+			key:  "user.email",
+			want: []Value{{"work@example.com"}, {"me@example.com"}},
+		},
+		{
+			name: "Missing Include Is Ignored",
+			files: map[string]string{
+				"config": "[include]\n\tpath = missing\n[user]\n\temail = me@example.com\n",
+			},
+			key:  "user.email",
+			want: []Value{{"me@example.com"}},
+		},
+		{
+			name: "Conditional Include Without Condition Func",
+			files: map[string]string{
+				"config": "[includeIf \"onbranch:main\"]\n\tpath = ~/profiles/work.gitconfig\n",
+			},
+			key:     "user.email",
+			wantErr: ErrKeyNotFound,
+		},
+		{
+			name: "Conditional Include",
+			files: map[string]string{
+				"config": "[includeIf \"onbranch:main\"]\n\tpath = ~/profiles/work.gitconfig\n" +
+					"[includeIf \"onbranch:dev\"]\n\tpath = missing\n",
+			},
+			opts: LoadOptions{
+				IncludeIf: func(condition, _ string) (bool, error) {
+					return condition == "onbranch:main", nil
+				},
+			},
+			key:  "user.email",
+			want: []Value{{"work@example.com"}},
+		},
+		{
+			name: "Circular Include",
+			files: map[string]string{
+				"config": "[include]\n\tpath = other\n",
+				"other":  "[include]\n\tpath = config\n",
+			},
+			wantErr: ErrIncludeCycle,
+		},
+		{
+			name: "Include Too Deep",
+			files: map[string]string{
+				"config": "[include]\n\tpath = 1\n",
+				"1":      "[include]\n\tpath = 2\n",
+				"2":      "[include]\n\tpath = 3\n",
+				"3":      "[user]\n\temail = deep@example.com\n",
+			},
+			opts:    LoadOptions{MaxDepth: 2},
+			wantErr: ErrIncludeDepth,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeConfigs(t, dir, tt.files)
+
+			gc, err := Load(filepath.Join(dir, "config"), tt.opts)
+			if err == nil {
+				_, err = gc.GetAll(tt.key)
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			got, _ := gc.GetAll(tt.key)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GitConfig.GetAll(%s) = %v, want %v", tt.key, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIncludeContext_Match(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	configPath := filepath.Join(home, ".gitconfig")
+	ctx := IncludeContext{
+		GitDir:     filepath.Join(home, "work", "project", ".git"),
+		Branch:     "refs/heads/feature/login",
+		RemoteURLs: []string{"git@github.com:work-org/project.git"},
+	}
+
+	tests := []struct {
+		name      string
+		condition string
+		path      string
+		want      bool
+		wantErr   error
+	}{
+		{name: "gitdir Home Directory", condition: "gitdir:~/work/", want: true},
+		{name: "gitdir Other Directory", condition: "gitdir:~/personal/"},
+		{name: "gitdir Without Leading Slash", condition: "gitdir:project/.git", want: true},
+		{name: "gitdir Without Trailing Slash", condition: "gitdir:~/work"},
+		{name: "gitdir Is Case Sensitive", condition: "gitdir:~/WORK/"},
+		{name: "gitdir/i", condition: "gitdir/i:~/WORK/", want: true},
+		{name: "gitdir Relative To Config File", condition: "gitdir:./work/**", path: configPath, want: true},
+		{name: "gitdir Relative Without Config File", condition: "gitdir:./work/", wantErr: ErrRelativeCondition},
+		{name: "onbranch", condition: "onbranch:feature/login", want: true},
+		{name: "onbranch Trailing Slash", condition: "onbranch:feature/", want: true},
+		{name: "onbranch Star Doesn't Match Slash", condition: "onbranch:feature*"},
+		{name: "onbranch Other Branch", condition: "onbranch:main"},
+		{name: "hasconfig Remote URL", condition: "hasconfig:remote.*.url:git@github.com:work-org/**", want: true},
+		{name: "hasconfig Other Remote URL", condition: "hasconfig:remote.*.url:https://github.com/**"},
+		{name: "Unknown Condition", condition: "foo:bar"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ctx.Match(tt.condition, tt.path)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("IncludeContext.Match(%q) error = %v, wantErr %v", tt.condition, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("IncludeContext.Match(%q) = %v, want %v", tt.condition, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoad_IncludeContext(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	writeConfigs(t, home, map[string]string{
+		".gitconfig": "[user]\n\temail = me@example.com\n" +
+			"[includeIf \"gitdir:~/work/\"]\n\tpath = .gitconfig-work\n" +
+			"[includeIf \"onbranch:oss/\"]\n\tpath = .gitconfig-oss\n",
+		".gitconfig-work": "[user]\n\temail = work@example.com\n",
+		".gitconfig-oss":  "[user]\n\temail = oss@example.com\n",
+	})
+
+	tests := []struct {
+		name string
+		ctx  IncludeContext
+		want string
+	}{
+		{
+			name: "Personal Repository",
+			ctx:  IncludeContext{GitDir: filepath.Join(home, "personal", "dotfiles", ".git"), Branch: "main"},
+			want: "me@example.com",
+		},
+		{
+			name: "Work Repository",
+			ctx:  IncludeContext{GitDir: filepath.Join(home, "work", "api", ".git"), Branch: "main"},
+			want: "work@example.com",
+		},
+		{
+			name: "Work Repository On OSS Branch",
+			ctx:  IncludeContext{GitDir: filepath.Join(home, "work", "api", ".git"), Branch: "oss/fix"},
+			want: "oss@example.com",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gc, err := Load(filepath.Join(home, ".gitconfig"), LoadOptions{IncludeIf: tt.ctx.Match})
+			if err != nil {
+				t.Fatalf("Load() error = %v, want %v", err, nil)
+			}
+			got, err := gc.Get("user.email")
+			if err != nil || got.String() != tt.want {
+				t.Errorf("GitConfig.Get() = (%v, %v), want (%v, %v)", got, err, tt.want, nil)
+			}
+		})
+	}
+}
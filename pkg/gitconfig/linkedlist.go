@@ -13,6 +13,10 @@ func (l *list[T]) front() *node[T] {
 	return l.root.next
 }
 
+func (l *list[T]) back() *node[T] {
+	return l.root.prev
+}
+
 func (l *list[T]) pushBack(val T) *node[T] {
 	node := &node[T]{
 		val: val,
@@ -31,6 +35,33 @@ func (l *list[T]) pushBack(val T) *node[T] {
 	return node
 }
 
+// insertAfter inserts val right after mark. If mark is nil, val is
+// inserted at the front of the list.
+func (l *list[T]) insertAfter(mark *node[T], val T) *node[T] {
+	node := &node[T]{
+		val: val,
+	}
+
+	if mark == nil {
+		node.next = l.root.next
+	} else {
+		node.prev = mark
+		node.next = mark.next
+		mark.next = node
+	}
+
+	if node.next == nil {
+		l.root.prev = node
+	} else {
+		node.next.prev = node
+	}
+	if node.prev == nil {
+		l.root.next = node
+	}
+
+	return node
+}
+
 func (l *list[T]) remove(node *node[T]) {
 	if node.prev == nil {
 		l.root.next = node.next
@@ -38,7 +69,9 @@ func (l *list[T]) remove(node *node[T]) {
 		node.prev.next = node.next
 	}
 
-	if node.next != nil {
+	if node.next == nil {
+		l.root.prev = node.prev
+	} else {
 		node.next.prev = node.prev
 	}
 }
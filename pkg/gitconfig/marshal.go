@@ -0,0 +1,469 @@
+package gitconfig
+
+import (
+	"encoding"
+	"errors"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+const tagName = "gitconfig"
+
+var (
+	valueType           = reflect.TypeOf(Value{})
+	textMarshalerType   = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// Marshal returns the config file representation of v, which must be a struct
+// or a pointer to a struct. See GitConfig.Encode() for how fields are mapped to keys.
+func Marshal(v interface{}) ([]byte, error) {
+	g := New()
+	err := g.Encode(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return g.MarshalText()
+}
+
+// Unmarshal parses data and stores its values in the struct pointed to by v.
+// See GitConfig.Decode() for how keys are mapped to fields.
+func Unmarshal(data []byte, v interface{}) error {
+	g, err := Parse(data)
+	if err != nil {
+		return err
+	}
+
+	return g.Decode(v)
+}
+
+// Encode sets the keys of g from the fields of v, which must be a struct or a
+// pointer to a struct. Fields are mapped to keys with the "gitconfig" tag:
+//
+//	type Config struct {
+//		Name    string            `gitconfig:"user.name"`
+//		Sign    bool              `gitconfig:"commit.gpgSign,omitempty"`
+//		Aliases map[string]string `gitconfig:"alias"`
+//		Remotes map[string]Remote `gitconfig:"remote"`
+//	}
+//
+//	type Remote struct {
+//		URL   string   `gitconfig:"url"`
+//		Fetch []string `gitconfig:"fetch"`
+//	}
+//
+// The tag of a struct field is the prefix of the keys of its own fields, so
+// Remotes["origin"].Fetch is stored in remote.origin.fetch. A map of structs
+// holds the subsections of a section, any other map holds the variables of a
+// section and a slice holds all values of a multi-valued key. Fields without a
+// tag, or tagged "-", are ignored.
+//
+// Strings are stored as is (see EncodeValue()), fields of type Value are
+// stored in config file syntax and types implementing encoding.TextMarshaler
+// are stored as their text. A nil pointer, or a zero value of a field with the
+// "omitempty" option, leaves the key untouched. An empty slice removes the key.
+func (g *GitConfig) Encode(v interface{}) error {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return ErrInvalidValueType
+	}
+
+	return g.encodeStruct("", rv)
+}
+
+// Decode stores the values of g in the fields of the struct pointed to by v.
+// Fields are mapped to keys the same way as GitConfig.Encode() does.
+//
+// A field of a single value gets the last value of its key, strings are decoded
+// with Value.String(), booleans with Value.Bool() and integers with Value.Int().
+// Fields whose keys don't exist are left unchanged.
+func (g GitConfig) Decode(v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return ErrInvalidValueType
+	}
+
+	_, err := g.decodeStruct("", rv.Elem())
+	return err
+}
+
+// fieldKey returns the key f is mapped to, relative to prefix.
+func fieldKey(prefix string, f reflect.StructField) (key string, omitEmpty, ok bool) {
+	tag, ok := f.Tag.Lookup(tagName)
+	if !ok || !f.IsExported() {
+		return "", false, false
+	}
+	name, opts, _ := strings.Cut(tag, ",")
+	if len(name) == 0 || name == "-" {
+		return "", false, false
+	}
+	omitEmpty = slices.Contains(strings.Split(opts, ","), "omitempty")
+
+	if len(prefix) > 0 {
+		name = prefix + "." + name
+	}
+
+	return name, omitEmpty, true
+}
+
+// isScalar reports whether values of t are stored in a single config value.
+func isScalar(t reflect.Type) bool {
+	if t == valueType {
+		return true
+	}
+	if t.Kind() == reflect.Pointer {
+		return isScalar(t.Elem())
+	}
+	if t.Implements(textMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType) ||
+		reflect.PointerTo(t).Implements(textUnmarshalerType) {
+		return true
+	}
+
+	switch t.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+	return false
+}
+
+// isSubsections reports whether a map of t holds subsections.
+func isSubsections(t reflect.Type) bool {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Struct && !isScalar(t)
+}
+
+func (g *GitConfig) encodeStruct(prefix string, rv reflect.Value) error {
+	t := rv.Type()
+	for i := 0; i < t.NumField(); i++ {
+		key, omitEmpty, ok := fieldKey(prefix, t.Field(i))
+		if !ok {
+			continue
+		}
+		fv := rv.Field(i)
+		if omitEmpty && fv.IsZero() {
+			continue
+		}
+
+		err := g.encodeField(key, fv)
+		if err != nil {
+			return fieldError(err, key, t.Name()+"."+t.Field(i).Name)
+		}
+	}
+
+	return nil
+}
+
+func (g *GitConfig) encodeField(key string, fv reflect.Value) error {
+	t := fv.Type()
+	if isScalar(t) {
+		if t.Kind() == reflect.Pointer && fv.IsNil() {
+			return nil
+		}
+		val, err := encodeValue(fv)
+		if err != nil {
+			return err
+		}
+		return g.setValues(key, val)
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		if fv.IsNil() {
+			return nil
+		}
+		return g.encodeField(key, fv.Elem())
+	case reflect.Struct:
+		return g.encodeStruct(key, fv)
+	case reflect.Slice:
+		if !isScalar(t.Elem()) {
+			return ErrInvalidValueType
+		}
+		vals := make([]Value, 0, fv.Len())
+		for i := 0; i < fv.Len(); i++ {
+			if fv.Index(i).Kind() == reflect.Pointer && fv.Index(i).IsNil() {
+				continue
+			}
+			val, err := encodeValue(fv.Index(i))
+			if err != nil {
+				return err
+			}
+			vals = append(vals, val)
+		}
+		return g.setValues(key, vals...)
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String {
+			return ErrInvalidValueType
+		}
+		names := fv.MapKeys()
+		slices.SortFunc(names, func(a, b reflect.Value) int {
+			return strings.Compare(a.String(), b.String())
+		})
+		for _, name := range names {
+			err := g.encodeField(key+"."+name.String(), fv.MapIndex(name))
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	return ErrInvalidValueType
+}
+
+// setValues replaces the values of key with vals, or removes the key if vals is empty.
+func (g *GitConfig) setValues(key string, vals ...Value) error {
+	section, name, err := g.splitKey(key)
+	if err != nil {
+		return err
+	}
+	if len(vals) == 0 {
+		err = g.unset(section, name)
+		if errors.Is(err, ErrKeyNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	g.set(section, name, vals...)
+	return nil
+}
+
+func encodeValue(fv reflect.Value) (Value, error) {
+	t := fv.Type()
+	if t == valueType {
+		val := fv.Interface().(Value)
+		if s, ok := val.v.(string); ok {
+			return val, ValidateValue(s)
+		}
+		return val, nil
+	}
+	if t.Kind() == reflect.Pointer {
+		if fv.IsNil() {
+			return Value{}, ErrEmptyValue
+		}
+		return encodeValue(fv.Elem())
+	}
+
+	var marshaler encoding.TextMarshaler
+	switch {
+	case t.Implements(textMarshalerType):
+		marshaler = fv.Interface().(encoding.TextMarshaler)
+	case fv.CanAddr() && reflect.PointerTo(t).Implements(textMarshalerType):
+		marshaler = fv.Addr().Interface().(encoding.TextMarshaler)
+	}
+	if marshaler != nil {
+		text, err := marshaler.MarshalText()
+		if err != nil {
+			return Value{}, err
+		}
+		return Value{EncodeValue(string(text))}, nil
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return Value{EncodeValue(fv.String())}, nil
+	case reflect.Bool:
+		return Value{fv.Bool()}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return Value{fv.Int()}, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return Value{fv.Uint()}, nil
+	}
+
+	return Value{}, ErrInvalidValueType
+}
+
+// decodeStruct decodes the fields of rv, it reports whether any key was found.
+func (g GitConfig) decodeStruct(prefix string, rv reflect.Value) (bool, error) {
+	var found bool
+	t := rv.Type()
+	for i := 0; i < t.NumField(); i++ {
+		key, _, ok := fieldKey(prefix, t.Field(i))
+		if !ok {
+			continue
+		}
+
+		ok, err := g.decodeField(key, rv.Field(i))
+		if err != nil {
+			return false, fieldError(err, key, t.Name()+"."+t.Field(i).Name)
+		}
+		found = found || ok
+	}
+
+	return found, nil
+}
+
+// decodeField decodes the values of key into fv, it reports whether key was found.
+func (g GitConfig) decodeField(key string, fv reflect.Value) (bool, error) {
+	t := fv.Type()
+	if isScalar(t) {
+		vals, err := g.GetAll(key)
+		if errors.Is(err, ErrKeyNotFound) {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		return true, decodeValue(vals[len(vals)-1], fv)
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		elem := reflect.New(t.Elem())
+		if !fv.IsNil() {
+			elem.Elem().Set(fv.Elem())
+		}
+		found, err := g.decodeField(key, elem.Elem())
+		if found && err == nil {
+			fv.Set(elem)
+		}
+		return found, err
+	case reflect.Struct:
+		return g.decodeStruct(key, fv)
+	case reflect.Slice:
+		if !isScalar(t.Elem()) {
+			return false, ErrInvalidValueType
+		}
+		vals, err := g.GetAll(key)
+		if errors.Is(err, ErrKeyNotFound) {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		s := reflect.MakeSlice(t, len(vals), len(vals))
+		for i := range vals {
+			err = decodeValue(vals[i], s.Index(i))
+			if err != nil {
+				return false, err
+			}
+		}
+		fv.Set(s)
+		return true, nil
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String {
+			return false, ErrInvalidValueType
+		}
+		names, err := g.mapKeys(key, isSubsections(t.Elem()))
+		if err != nil || len(names) == 0 {
+			return false, err
+		}
+		if fv.IsNil() {
+			fv.Set(reflect.MakeMap(t))
+		}
+		for _, name := range names {
+			k := reflect.ValueOf(name).Convert(t.Key())
+			elem := reflect.New(t.Elem()).Elem()
+			if existing := fv.MapIndex(k); existing.IsValid() {
+				elem.Set(existing)
+			}
+			_, err = g.decodeField(key+"."+name, elem)
+			if err != nil {
+				return false, err
+			}
+			fv.SetMapIndex(k, elem)
+		}
+		return true, nil
+	}
+
+	return false, ErrInvalidValueType
+}
+
+// mapKeys returns the subsections of section name if subsections is true,
+// or else the variable names of section name.
+func (g GitConfig) mapKeys(name string, subsections bool) ([]string, error) {
+	var keys []string
+	if subsections {
+		name = strings.ToLower(name)
+		for _, sec := range g.data.keys() {
+			if sec.Name == name && len(sec.Subsection) > 0 {
+				keys = append(keys, sec.Subsection)
+			}
+		}
+		return keys, nil
+	}
+
+	sec, err := NewSection(name)
+	if err != nil {
+		return nil, err
+	}
+	variables, ok := g.data.get(sec.canonical())
+	if !ok {
+		return nil, nil
+	}
+	for _, name := range variables.keys() {
+		keys = append(keys, string(variables.mustGet(name)[0].val.name))
+	}
+
+	return keys, nil
+}
+
+func decodeValue(val Value, fv reflect.Value) error {
+	t := fv.Type()
+	if t == valueType {
+		fv.Set(reflect.ValueOf(val))
+		return nil
+	}
+	if t.Kind() == reflect.Pointer {
+		elem := reflect.New(t.Elem())
+		err := decodeValue(val, elem.Elem())
+		if err != nil {
+			return err
+		}
+		fv.Set(elem)
+		return nil
+	}
+	if reflect.PointerTo(t).Implements(textUnmarshalerType) {
+		return fv.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(val.String()))
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		fv.SetString(val.String())
+	case reflect.Bool:
+		b, err := val.Bool()
+		if err != nil {
+			return err
+		}
+		fv.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := val.Int()
+		if err != nil {
+			return err
+		}
+		if fv.OverflowInt(n) {
+			return ErrInvalidNumber
+		}
+		fv.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := val.Int()
+		if err != nil {
+			return err
+		}
+		if n < 0 || fv.OverflowUint(uint64(n)) {
+			return ErrInvalidNumber
+		}
+		fv.SetUint(uint64(n))
+	default:
+		return ErrInvalidValueType
+	}
+
+	return nil
+}
+
+func fieldError(err error, key, field string) error {
+	var fe *FieldError
+	if errors.As(err, &fe) {
+		return err
+	}
+	return &FieldError{Err: err, Key: key, Field: field}
+}
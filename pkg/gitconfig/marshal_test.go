@@ -0,0 +1,228 @@
+package gitconfig
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type testFormat string
+
+func (f *testFormat) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		return ErrEmptyValue
+	}
+	*f = testFormat(strings.ToLower(string(text)))
+	return nil
+}
+
+func (f testFormat) MarshalText() ([]byte, error) {
+	return []byte(f), nil
+}
+
+type testRemote struct {
+	URL      string   `gitconfig:"url"`
+	PushURLs []string `gitconfig:"pushurl,omitempty"`
+	Fetch    []string `gitconfig:"fetch"`
+}
+
+type testConfig struct {
+	User struct {
+		Name       string `gitconfig:"name"`
+		Email      string `gitconfig:"email"`
+		SigningKey string `gitconfig:"signingKey,omitempty"`
+	} `gitconfig:"user"`
+	GPGFormat testFormat            `gitconfig:"gpg.format,omitempty"`
+	Sign      *bool                 `gitconfig:"commit.gpgSign"`
+	Window    int32                 `gitconfig:"pack.window,omitempty"`
+	Bare      Value                 `gitconfig:"core.bare,omitempty"`
+	Aliases   map[string]string     `gitconfig:"alias"`
+	Remotes   map[string]testRemote `gitconfig:"remote"`
+	Ignored   string
+	Skipped   string `gitconfig:"-"`
+}
+
+func TestUnmarshal(t *testing.T) {
+	data := `[user]
+	name = John Doe
+	email = "john@example.com"
+[gpg]
+	format = SSH
+[commit]
+	gpgsign
+[pack]
+	window = 1k
+[core]
+	bare = false
+[alias]
+	co = checkout
+	st = status -sb
+[remote "origin"]
+	url = git@github.com:thansetan/git-sw.git
+	fetch = +refs/heads/*:refs/remotes/origin/*
+	fetch = +refs/tags/*:refs/tags/*
+[remote "Upstream"]
+	url = https://github.com/thansetan/git-sw
+`
+	var got testConfig
+	got.Ignored = "unchanged"
+	err := Unmarshal([]byte(data), &got)
+	if err != nil {
+		t.Fatalf("Unmarshal() error = %v, want %v", err, nil)
+	}
+
+	sign := true
+	var want testConfig
+	want.User.Name = "John Doe"
+	want.User.Email = "john@example.com"
+	want.GPGFormat = "ssh"
+	want.Sign = &sign
+	want.Window = 1024
+	want.Bare = Value{"false"}
+	want.Aliases = map[string]string{"co": "checkout", "st": "status -sb"}
+	want.Remotes = map[string]testRemote{
+		"origin": {
+			URL:   "git@github.com:thansetan/git-sw.git",
+			Fetch: []string{"+refs/heads/*:refs/remotes/origin/*", "+refs/tags/*:refs/tags/*"},
+		},
+		"Upstream": {URL: "https://github.com/thansetan/git-sw"},
+	}
+	want.Ignored = "unchanged"
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Unmarshal() = %+v, want %+v", got, want)
+	}
+}
+
+func TestUnmarshal_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		v       interface{}
+		wantErr error
+	}{
+		{
+			name:    "Not A Pointer",
+			data:    "[user]\n\tname = me\n",
+			v:       testConfig{},
+			wantErr: ErrInvalidValueType,
+		},
+		{
+			name:    "Invalid Bool",
+			data:    "[commit]\n\tgpgsign = maybe\n",
+			v:       &testConfig{},
+			wantErr: ErrInvalidBool,
+		},
+		{
+			name:    "Overflow",
+			data:    "[pack]\n\twindow = 4g\n",
+			v:       &testConfig{},
+			wantErr: ErrInvalidNumber,
+		},
+		{
+			name:    "Text Unmarshaler",
+			data:    "[gpg]\n\tformat =\n",
+			v:       &testConfig{},
+			wantErr: ErrEmptyValue,
+		},
+		{
+			name: "Unsupported Type",
+			data: "[core]\n\tcompression = 1\n",
+			v: &struct {
+				Compression float64 `gitconfig:"core.compression"`
+			}{},
+			wantErr: ErrInvalidValueType,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Unmarshal([]byte(tt.data), tt.v)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Unmarshal() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	var fe *FieldError
+	err := Unmarshal([]byte("[commit]\n\tgpgsign = maybe\n"), &testConfig{})
+	if !errors.As(err, &fe) || fe.Key != "commit.gpgSign" || fe.Field != "testConfig.Sign" {
+		t.Errorf("Unmarshal() error = %v, want a *FieldError for %s", err, "testConfig.Sign")
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	sign := true
+	var v testConfig
+	v.User.Name = "John Doe"
+	v.User.Email = " john@example.com"
+	v.GPGFormat = "ssh"
+	v.Sign = &sign
+	v.Aliases = map[string]string{"st": "status -sb", "co": "checkout"}
+	v.Remotes = map[string]testRemote{
+		"origin": {
+			URL:   "git@github.com:thansetan/git-sw.git",
+			Fetch: []string{"+refs/heads/*:refs/remotes/origin/*", "+refs/tags/*:refs/tags/*"},
+		},
+	}
+	v.Ignored = "ignored"
+
+	got, err := Marshal(v)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v, want %v", err, nil)
+	}
+	want := `[user]
+	name = John Doe
+	email = " john@example.com"
+[gpg]
+	format = ssh
+[commit]
+	gpgSign = true
+[alias]
+	co = checkout
+	st = status -sb
+[remote "origin"]
+	url = git@github.com:thansetan/git-sw.git
+	fetch = +refs/heads/*:refs/remotes/origin/*
+	fetch = +refs/tags/*:refs/tags/*
+`
+	if string(got) != want {
+		t.Errorf("Marshal() = %q, want %q", got, want)
+	}
+
+	var decoded testConfig
+	decoded.Ignored = "ignored"
+	err = Unmarshal(got, &decoded)
+	if err != nil {
+		t.Fatalf("Unmarshal() error = %v, want %v", err, nil)
+	}
+	if !reflect.DeepEqual(decoded, v) {
+		t.Errorf("Unmarshal(Marshal()) = %+v, want %+v", decoded, v)
+	}
+}
+
+func TestGitConfig_Encode(t *testing.T) {
+	gc, err := Parse([]byte("# my remotes\n[remote \"origin\"]\n\turl = old\n\tpushurl = a\n\tpushurl = b\n\tfetch = +refs/heads/*:refs/remotes/origin/*\n"))
+	if err != nil {
+		t.Fatalf("Parse() error = %v, want %v", err, nil)
+	}
+
+	err = gc.Encode(struct {
+		Origin testRemote `gitconfig:"remote.origin"`
+	}{testRemote{URL: "new", PushURLs: []string{"c"}}})
+	if err != nil {
+		t.Fatalf("GitConfig.Encode() error = %v, want %v", err, nil)
+	}
+
+	got, _ := gc.MarshalText()
+	want := "# my remotes\n[remote \"origin\"]\n\turl = new\n\tpushurl = c\n"
+	if string(got) != want {
+		t.Errorf("GitConfig.Encode() = %q, want %q", got, want)
+	}
+
+	err = gc.Encode(struct {
+		Name string `gitconfig:"user"`
+	}{"me"})
+	if !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("GitConfig.Encode() error = %v, want %v", err, ErrInvalidKey)
+	}
+}
@@ -0,0 +1,105 @@
+package gitconfig
+
+// MergeStrategy tells how Merge combines the values of a key found in
+// more than one config.
+type MergeStrategy int
+
+const (
+	// MergeReplace replaces the values of the key with the ones of the overlay.
+	MergeReplace MergeStrategy = iota
+	// MergeAppend adds the values of the overlay after the existing ones, the
+	// same way git combines the values of included files.
+	MergeAppend
+	// MergeDedupe is like MergeAppend, but values that are already present
+	// aren't added again.
+	MergeDedupe
+)
+
+// MergeOptions controls how MergeWith combines configs.
+type MergeOptions struct {
+	// Strategy is used for every key without a strategy in Keys.
+	// If zero, MergeReplace is used.
+	Strategy MergeStrategy
+	// Keys sets the strategy of specific keys, e.g. {"include.path": MergeAppend}.
+	Keys map[string]MergeStrategy
+}
+
+// Merge returns a new config made of base with every overlay applied on top
+// of it, in order. Values of keys found in an overlay replace the ones of base.
+// See MergeWith() to choose how values are combined.
+func Merge(base *GitConfig, overlays ...*GitConfig) *GitConfig {
+	merged, _ := MergeWith(MergeOptions{}, base, overlays...)
+	return merged
+}
+
+// MergeWith returns a new config made of base with every overlay applied on
+// top of it, in order, combining the values of keys as opts says. The lines of
+// base, comments included, are kept and keys that are new to base are added at
+// the end of their section. base and the overlays aren't modified, nil configs
+// are skipped.
+func MergeWith(opts MergeOptions, base *GitConfig, overlays ...*GitConfig) (*GitConfig, error) {
+	strategies := make(map[Key]MergeStrategy, len(opts.Keys))
+	for key, strategy := range opts.Keys {
+		section, name, err := GitConfig{}.splitKey(key)
+		if err != nil {
+			return nil, err
+		}
+		strategies[Key{section.canonical(), name.canonical()}] = strategy
+	}
+
+	merged := New()
+	if base != nil {
+		merged = base.clone()
+	}
+
+	for _, overlay := range overlays {
+		if overlay == nil {
+			continue
+		}
+		for _, key := range overlay.Keys() {
+			vals, _ := overlay.get(key.Section, key.Name)
+			strategy, ok := strategies[Key{key.Section.canonical(), key.Name.canonical()}]
+			if !ok {
+				strategy = opts.Strategy
+			}
+			merged.merge(key, vals, strategy)
+		}
+	}
+
+	return merged, nil
+}
+
+func (g *GitConfig) merge(key Key, vals []Value, strategy MergeStrategy) {
+	switch strategy {
+	case MergeAppend:
+		g.add(key.Section, key.Name, vals...)
+	case MergeDedupe:
+		existing, _ := g.get(key.Section, key.Name)
+		for _, val := range vals {
+			if !containsValue(existing, val) {
+				g.add(key.Section, key.Name, val)
+				existing = append(existing, val)
+			}
+		}
+	default:
+		g.set(key.Section, key.Name, vals...)
+	}
+}
+
+func containsValue(vals []Value, val Value) bool {
+	for i := range vals {
+		if equalValue(vals[i], val) {
+			return true
+		}
+	}
+	return false
+}
+
+// clone returns a deep copy of g.
+func (g GitConfig) clone() *GitConfig {
+	c := New()
+	for e := g.lines.front(); e != nil; e = e.next {
+		c.appendLine(e.val)
+	}
+	return c
+}
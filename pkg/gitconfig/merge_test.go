@@ -0,0 +1,120 @@
+package gitconfig
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMergeWith(t *testing.T) {
+	base := "# default profile\n" +
+		"[user]\n" +
+		"\tname = John Doe\n" +
+		"\temail = john@example.com\n" +
+		"[include]\n" +
+		"\tpath = a.inc\n" +
+		"\tpath = b.inc\n"
+	overlays := []string{
+		"[user]\n\temail = john@work.com\n[include]\n\tpath = b.inc\n\tpath = c.inc\n",
+		"[commit]\n\tgpgsign = true\n",
+	}
+
+	tests := []struct {
+		name    string
+		opts    MergeOptions
+		want    string
+		wantErr error
+	}{
+		{
+			name: "Replace",
+			want: "# default profile\n" +
+				"[user]\n" +
+				"\tname = John Doe\n" +
+				"\temail = john@work.com\n" +
+				"[include]\n" +
+				"\tpath = b.inc\n" +
+				"\tpath = c.inc\n" +
+				"[commit]\n" +
+				"\tgpgsign = true\n",
+		},
+		{
+			name: "Append",
+			opts: MergeOptions{Strategy: MergeAppend},
+			want: "# default profile\n" +
+				"[user]\n" +
+				"\tname = John Doe\n" +
+				"\temail = john@example.com\n" +
+				"\temail = john@work.com\n" +
+				"[include]\n" +
+				"\tpath = a.inc\n" +
+				"\tpath = b.inc\n" +
+				"\tpath = b.inc\n" +
+				"\tpath = c.inc\n" +
+				"[commit]\n" +
+				"\tgpgsign = true\n",
+		},
+		{
+			name: "Dedupe Single Key",
+			opts: MergeOptions{Keys: map[string]MergeStrategy{"Include.Path": MergeDedupe}},
+			want: "# default profile\n" +
+				"[user]\n" +
+				"\tname = John Doe\n" +
+				"\temail = john@work.com\n" +
+				"[include]\n" +
+				"\tpath = a.inc\n" +
+				"\tpath = b.inc\n" +
+				"\tpath = c.inc\n" +
+				"[commit]\n" +
+				"\tgpgsign = true\n",
+		},
+		{
+			name:    "Invalid Key",
+			opts:    MergeOptions{Keys: map[string]MergeStrategy{"include": MergeAppend}},
+			wantErr: ErrInvalidKey,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := Parse([]byte(base))
+			if err != nil {
+				t.Fatalf("Parse() error = %v, want %v", err, nil)
+			}
+			configs := make([]*GitConfig, 0, len(overlays))
+			for _, overlay := range overlays {
+				o, err := Parse([]byte(overlay))
+				if err != nil {
+					t.Fatalf("Parse() error = %v, want %v", err, nil)
+				}
+				configs = append(configs, o)
+			}
+
+			merged, err := MergeWith(tt.opts, b, configs...)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("MergeWith() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			got, _ := merged.MarshalText()
+			if string(got) != tt.want {
+				t.Errorf("MergeWith() = %q, want %q", got, tt.want)
+			}
+			if unchanged, _ := b.MarshalText(); string(unchanged) != base {
+				t.Errorf("MergeWith() modified base = %q, want %q", unchanged, base)
+			}
+		})
+	}
+}
+
+func TestMerge(t *testing.T) {
+	overlay, _ := Parse([]byte("[user]\n\tname = Jane\n"))
+
+	merged := Merge(nil, nil, overlay)
+	name, err := merged.Get("user.name")
+	if err != nil || name.String() != "Jane" {
+		t.Errorf("Merge().Get() = (%v, %v), want (%v, %v)", name, err, "Jane", nil)
+	}
+	merged.Set("user.name", "John")
+	if name, _ := overlay.Get("user.name"); name.String() != "Jane" {
+		t.Errorf("Merge() shares lines with overlay, got %v, want %v", name, "Jane")
+	}
+}
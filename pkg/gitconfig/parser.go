@@ -1,8 +1,10 @@
 package gitconfig
 
 import (
+	"bytes"
+	"errors"
 	"io"
-	"unicode"
+	"strings"
 )
 
 type lineType int
@@ -12,18 +14,47 @@ const (
 	comment
 	section
 	variable
+	blank
 	end
 )
 
+// ParseReader reads a config file from r and parses it. See Parse().
+func ParseReader(r io.Reader) (*GitConfig, error) {
+	in, err := io.ReadAll(r)
+	if err != nil {
+		return &GitConfig{}, err
+	}
+
+	return Parse(in)
+}
+
+// Parse parses the content of a config file. It stops at the first line
+// that can't be parsed and returns a *ParseError.
 func Parse(in []byte) (*GitConfig, error) {
+	return ParseWith(in, ParseOptions{})
+}
+
+// ParseOptions controls how ParseWith parses a config file.
+type ParseOptions struct {
+	// Lenient makes ParseWith skip the lines that can't be parsed instead of
+	// stopping at the first one. The lines following an invalid section header
+	// are skipped as well, up to the next valid header, as their section is
+	// unknown. Saving the returned config drops every skipped line.
+	Lenient bool
+}
+
+// ParseWith parses the content of a config file. In lenient mode, the config
+// made of every line that could be parsed is returned along with a ParseErrors
+// holding all the errors found, if any.
+func ParseWith(in []byte, opts ParseOptions) (*GitConfig, error) {
 	c := new(configFile)
 	c.init(in)
-	gc, err := c.parse()
-	if err != nil {
+	gc, err := c.parse(opts)
+	if err != nil && !opts.Lenient {
 		return &GitConfig{}, err
 	}
 
-	return gc, nil
+	return gc, err
 }
 
 type configFile struct {
@@ -33,6 +64,13 @@ type configFile struct {
 	off        int  // curr position
 	n          int  // num of chars
 	cline      int  // current line number
+
+	// spans of the parts of the last line read by nextLine, -1 if the line doesn't have them
+	nameStart, nameEnd   int // section header or variable name
+	valueStart, valueEnd int
+	commentStart         int
+	lineEnd              int // end of the line, without its terminator
+	errOff               int // position of the last error, -1 if unknown
 }
 
 func (c *configFile) init(data []byte) {
@@ -50,7 +88,7 @@ func (c *configFile) lineString() string {
 		_, _ = c.readCh()
 	}
 
-	if c.data[c.off-1] == '\r' {
+	if c.off > c.lstart && c.data[c.off-1] == '\r' {
 		c.off--
 	}
 
@@ -89,8 +127,9 @@ func (c *configFile) toEndOfLine() error {
 	return nil
 }
 
+// trimSpaceLeft skips whitespace up to the end of the current line.
 func (c *configFile) trimSpaceLeft() {
-	for unicode.IsSpace(rune(c.nextCh())) {
+	for c.nextCh() != '\n' && isSpace(c.nextCh()) {
 		_, err := c.readCh()
 		if err != nil {
 			break
@@ -100,7 +139,7 @@ func (c *configFile) trimSpaceLeft() {
 
 func (c *configFile) trimSpaceRight() {
 	i := len(c.buff) - 1
-	for ; i >= 0 && unicode.IsSpace(rune(c.buff[i])); i-- {
+	for ; i >= 0 && isSpace(c.buff[i]); i-- {
 	}
 	c.buff = c.buff[:i+1]
 }
@@ -114,6 +153,8 @@ func (c configFile) getType() lineType {
 		return section
 	case ';', '#':
 		return comment
+	case '\n', '\r':
+		return blank
 	case 0:
 		return end
 	}
@@ -126,149 +167,269 @@ func (c *configFile) removeCarriageReturn() {
 	}
 }
 
-func (c *configFile) parse() (*GitConfig, error) {
+func (c *configFile) parse(opts ParseOptions) (*GitConfig, error) {
 	var (
-		err error
-		sec Section
+		sec  Section
+		skip bool // the header of the current block is invalid
+		errs ParseErrors
 	)
 	gc := New()
 
-loop:
-	for !c.eof {
-		c.trimSpaceLeft()
-		switch c.getType() {
-		case section:
-			sec, err = c.parseSection()
-			if err != nil {
-				return nil, &ParseError{
-					Err:        err,
-					Line:       c.lineString(),
-					LineNumber: c.cline,
-				}
-			}
-		case variable:
-			name, err := c.parseVariable()
-			if err != nil {
-				return nil, &ParseError{
-					Err:        err,
-					Line:       c.lineString(),
-					LineNumber: c.cline,
-				}
-			}
-			gc.add(sec, name, Value{string(c.buff)})
-		case comment, end:
-			err = c.toEndOfLine()
-			if err != nil {
-				break loop
+	for c.off < c.n {
+		line, err := c.nextLine(sec)
+		if err != nil {
+			pe := new(ParseError)
+			if !opts.Lenient || !errors.As(err, &pe) {
+				return nil, err
 			}
-		default:
-			return nil, &ParseError{
-				Err:        ErrInvalidLine,
-				Line:       c.lineString(),
-				LineNumber: c.cline,
+			errs = append(errs, pe)
+			skip = skip || line.typ == section
+			// skip the rest of the line
+			if c.toEndOfLine() == nil {
+				_, _ = c.readCh()
 			}
+			continue
+		}
+		if line.typ == section {
+			sec, skip = line.section, false
+		}
+		if !skip {
+			gc.appendLine(line)
 		}
 	}
 
+	if len(errs) > 0 {
+		return gc, errs
+	}
 	return gc, nil
 }
 
+// nextLine parses the line starting at the current position, including its
+// continuations, and moves to the start of the next one. sec is the section
+// the line belongs to if it's a variable. On error, the returned line only has its type.
+func (c *configFile) nextLine(sec Section) (syntaxNode, error) {
+	start := c.off
+	c.nameStart, c.nameEnd, c.valueStart, c.valueEnd, c.commentStart = -1, -1, -1, -1, -1
+	c.errOff = -1
+
+	c.trimSpaceLeft()
+	line := syntaxNode{typ: c.getType(), lineNumber: c.cline}
+	switch line.typ {
+	case section:
+		c.nameStart = c.off
+		sec, err := c.parseSection()
+		if err != nil {
+			return line, c.parseError(err)
+		}
+		line.section = sec
+		c.nameEnd = c.off
+		// like git, a variable (or another header) may follow the header on
+		// the same line, it's read as a line of its own that has no indentation
+		c.trimSpaceLeft()
+		switch c.getType() {
+		case variable, section:
+			c.off = c.nameEnd
+			c.lineEnd = c.off
+			line.raw = string(c.data[start:c.off])
+			return line, nil
+		case comment:
+			c.commentStart = c.off
+		case blank, end:
+		default:
+			return line, c.parseError(c.errorAt(c.off, ErrInvalidLine))
+		}
+	case variable:
+		c.nameStart = c.off
+		name, value, err := c.parseVariable()
+		if err != nil {
+			return line, c.parseError(err)
+		}
+		line.section, line.name, line.value = sec, name, value
+		c.nameEnd = c.nameStart + len(name)
+	case comment:
+		c.commentStart = c.off
+	case blank, end:
+	default:
+		return line, c.parseError(c.errorAt(c.off, ErrInvalidLine))
+	}
+
+	err := c.toEndOfLine()
+	c.lineEnd = c.off
+	if c.lineEnd > start && c.data[c.lineEnd-1] == '\r' {
+		c.lineEnd--
+	}
+	if err == nil {
+		_, _ = c.readCh()
+	}
+	line.raw = string(c.data[start:c.off])
+
+	return line, nil
+}
+
+// errorAt records that err was found at position off and returns it.
+func (c *configFile) errorAt(off int, err error) error {
+	c.errOff = off
+	return err
+}
+
+// parseError returns a *ParseError for err, found on the current line.
+func (c *configFile) parseError(err error) *ParseError {
+	off := c.errOff
+	if off < 0 {
+		off = c.off
+	}
+	lineStart := bytes.LastIndexByte(c.data[:off], '\n') + 1
+
+	return &ParseError{
+		Err:        err,
+		Code:       parseErrorCode(err),
+		Line:       c.lineString(),
+		LineNumber: c.cline,
+		Column:     off - lineStart + 1,
+		Offset:     off,
+	}
+}
+
+// commentStart returns the position of the comment between from and to,
+// or -1 if there's nothing but whitespace.
+func commentStart(data []byte, from, to int) int {
+	for i := from; i < to; i++ {
+		switch data[i] {
+		case ';', '#':
+			return i
+		case ' ', '\t', '\r', '\v', '\f':
+		default:
+			return -1
+		}
+	}
+	return -1
+}
+
 func (c *configFile) parseSection() (Section, error) {
+	var quoted, closed bool
+	start := c.off
 	c.buff = c.buff[:0]
 	// first char is a '[', drop it
 	_, _ = c.readCh()
 loop:
-	for {
+	for c.nextCh() != '\n' {
 		ch, err := c.readCh()
 		if err != nil {
 			break
 		}
-		switch ch {
-		case ']': // end of section
+		switch {
+		case ch == ']': // end of section
+			closed = true
 			break loop
-		case ' ': // probably have subsection
-			ch, err := c.readCh()
-			if err != nil {
-				break loop
-			}
-			if ch != '"' {
-				return Section{}, ErrInvalidLine
+		case isSpace(ch): // probably have subsection
+			c.trimSpaceLeft()
+			if c.nextCh() != '"' {
+				return Section{}, c.errorAt(c.off, ErrInvalidLine)
 			}
+			_, _ = c.readCh()
+			quoted = true
 			c.buff = append(c.buff, '.')
 			err = c.parseSubsection()
 			if err != nil {
 				return Section{}, err
 			}
+			// the closing quote must be followed by the closing bracket
+			if c.nextCh() != ']' {
+				return Section{}, c.errorAt(c.off, ErrInvalidLine)
+			}
+			_, _ = c.readCh()
+			closed = true
 			break loop
 		}
 		c.buff = append(c.buff, ch)
 	}
+	if !closed {
+		return Section{}, c.errorAt(c.off, ErrInvalidLine)
+	}
 
-	c.removeCarriageReturn()
 	sec, err := NewSection(string(c.buff))
 	if err != nil {
-		return Section{}, err
+		return Section{}, c.errorAt(start+1, err)
+	}
+	if !quoted {
+		// subsections of the deprecated [section.subsection] syntax are case-insensitive
+		sec.Subsection = strings.ToLower(sec.Subsection)
 	}
-
-	_ = c.toEndOfLine()
 
 	return sec, nil
 }
 
-// any characters except newline && null byte allowed
+// parseSubsection reads a quoted subsection up to and including its closing
+// quote. Any character but a newline is allowed, like git a backslash escapes
+// the character following it, which is kept as is.
 func (c *configFile) parseSubsection() error {
 	for {
+		if c.nextCh() == '\n' {
+			return c.errorAt(c.off, ErrInvalidSubsection)
+		}
 		ch, err := c.readCh()
 		if err != nil {
-			break
+			return c.errorAt(c.off, ErrInvalidSubsection)
 		}
-		if ch == '\\' && c.nextCh() == 'n' {
-			return ErrInvalidKey
+		switch ch {
+		case '"':
+			return nil
+		case '\\':
+			if c.nextCh() == '\n' || c.nextCh() == '\r' && c.off+1 < c.n && c.data[c.off+1] == '\n' {
+				return c.errorAt(c.off-1, ErrInvalidSubsection)
+			}
+			ch, err = c.readCh()
+			if err != nil {
+				return c.errorAt(c.off, ErrInvalidSubsection)
+			}
 		}
 		c.buff = append(c.buff, ch)
-		if c.nextCh() == '"' && ch != '\\' {
-			break
-		}
 	}
-	return nil
 }
 
-func (c *configFile) parseVariable() (VariableName, error) {
-	var spaceFound bool
+// parseVariable parses a variable line. A variable without '=' (e.g. "[core] bare")
+// has no value, which is represented by Value{nil}.
+func (c *configFile) parseVariable() (VariableName, Value, error) {
+	var spaceFound, hasValue bool
 	c.buff = c.buff[:0]
-	for { // parse variable name, only allows alphanumeric and '-'
+	for c.nextCh() != '\n' { // parse variable name, only allows alphanumeric and '-'
 		ch, err := c.readCh()
 		if err != nil {
 			break
 		}
 		if spaceFound && (isAlnum(ch) || ch == '-') {
-			return "", ErrInvalidVariableName
+			return "", Value{}, c.errorAt(c.off-1, ErrInvalidVariableName)
 		}
 		if ch == '=' {
+			hasValue = true
 			break
 		}
-		if unicode.IsSpace(rune(ch)) {
+		if isSpace(ch) {
 			spaceFound = true
 			continue
 		}
 		if !isAlnum(ch) && ch != '-' {
-			return "", ErrInvalidVariableName
+			return "", Value{}, c.errorAt(c.off-1, ErrInvalidVariableName)
 		}
 		c.buff = append(c.buff, ch)
 	}
 	name := VariableName(string(c.buff))
 	if !name.isValid() {
-		return "", ErrInvalidVariableName
+		return "", Value{}, c.errorAt(c.nameStart, ErrInvalidVariableName)
+	}
+	if !hasValue {
+		return name, Value{}, nil
 	}
 	c.trimSpaceLeft()
+	c.valueStart, c.valueEnd = c.off, c.off
 
 	err := c.parseValue()
 	if err != nil {
-		return "", err
+		return "", Value{}, err
 	}
 
 	c.removeCarriageReturn()
-	return name, nil
+	return name, Value{string(c.buff)}, nil
 }
 
 // allow any chars, '\' denotes value continues on the next line
@@ -279,16 +440,14 @@ func (c *configFile) parseValue() error {
 	)
 	c.buff = c.buff[:0]
 loop:
-	for {
+	for c.nextCh() != '\n' {
 		ch, err := c.readCh()
 		if err != nil {
 			break
 		}
 
-		if ch == '\n' {
-			break
-		}
 		if !isQuoted && (ch == ';' || ch == '#') {
+			c.commentStart = c.off - 1
 			_ = c.toEndOfLine()
 			break
 		}
@@ -305,17 +464,14 @@ loop:
 				sameLineAsKey = false
 				goto loop
 			case 't', 'b', 'n', '\\', '"':
-				if !isQuoted && c.nextCh() == 'n' {
-					c.cline--
-				}
 				c.buff = append(c.buff, ch)
 				ch, _ = c.readCh()
 				goto add
 			default:
 				if sameLineAsKey {
-					return ErrInvalidVariableValue
+					return c.errorAt(c.off-1, ErrInvalidVariableValue)
 				} else {
-					return ErrInvalidLine
+					return c.errorAt(c.off-1, ErrInvalidLine)
 				}
 			}
 		}
@@ -325,6 +481,12 @@ loop:
 		}
 	add:
 		c.buff = append(c.buff, ch)
+		if !isSpace(ch) {
+			c.valueEnd = c.off
+		}
+	}
+	if isQuoted {
+		return c.errorAt(c.off, ErrInvalidVariableValue)
 	}
 	c.trimSpaceRight()
 
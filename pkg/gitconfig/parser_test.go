@@ -5,6 +5,8 @@ import (
 	"errors"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 )
@@ -24,7 +26,10 @@ func TestParse(t *testing.T) {
 			configPath: "configsamples/badSectionName.gitconfig",
 			wantErr: &ParseError{
 				Err:        ErrInvalidSection,
+				Code:       CodeInvalidSection,
 				LineNumber: 1,
+				Column:     2,
+				Offset:     1,
 				Line:       "[fo!o] # section name can only contain alphanumeric characters and '-'",
 			},
 		},
@@ -33,7 +38,10 @@ func TestParse(t *testing.T) {
 			configPath: "configsamples/badVariableName.gitconfig",
 			wantErr: &ParseError{
 				Err:        ErrInvalidLine,
+				Code:       CodeInvalidLine,
 				LineNumber: 2,
+				Column:     2,
+				Offset:     7,
 				Line:       "	1bar = baz  # variable name must start with an alphabetic character",
 			},
 		},
@@ -42,7 +50,10 @@ func TestParse(t *testing.T) {
 			configPath: "configsamples/badVariableValue.gitconfig",
 			wantErr: &ParseError{
 				Err:        ErrInvalidVariableValue,
+				Code:       CodeInvalidVariableValue,
 				LineNumber: 2,
+				Column:     13,
+				Offset:     84,
 				Line:       `	bar = baz  \ # '\' indicates that the value continues on the next line, there MUST NOT be any character after '\'`,
 			},
 		},
@@ -81,12 +92,69 @@ func TestParse(t *testing.T) {
 				if !errors.Is(parseErr.Err, ttwantErr.Err) {
 					t.Errorf("expected error to be %s, got %s", ttwantErr, parseErr.Err)
 				}
+				if parseErr.Code != ttwantErr.Code {
+					t.Errorf("expected error code to be %s, got %s", ttwantErr.Code, parseErr.Code)
+				}
+				if parseErr.Column != ttwantErr.Column || parseErr.Offset != ttwantErr.Offset {
+					t.Errorf("expected error to be at column %d (offset %d), got column %d (offset %d)", ttwantErr.Column, ttwantErr.Offset, parseErr.Column, parseErr.Offset)
+				}
 			}
 		})
 	}
 
 }
 
+func TestParseWith_Lenient(t *testing.T) {
+	const config = "[core]\n" +
+		"\tbare = true\n" +
+		"\t1bare = false\n" +
+		"[a b]\n" +
+		"\tname = skipped\n" +
+		"[user]\n" +
+		"\tna!me = skipped\n" +
+		"\temail = me@example.com\n" +
+		"[remote \"origin\n" +
+		"\turl = skipped\n"
+
+	gc, err := ParseWith([]byte(config), ParseOptions{Lenient: true})
+	var errs ParseErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("ParseWith() error = %v, want %T", err, errs)
+	}
+
+	want := []ParseError{
+		{Err: ErrInvalidLine, Code: CodeInvalidLine, Line: "\t1bare = false", LineNumber: 3, Column: 2, Offset: 21},
+		{Err: ErrInvalidLine, Code: CodeInvalidLine, Line: "[a b]", LineNumber: 4, Column: 4, Offset: 38},
+		{Err: ErrInvalidVariableName, Code: CodeInvalidVariableName, Line: "\tna!me = skipped", LineNumber: 7, Column: 4, Offset: 67},
+		{Err: ErrInvalidSubsection, Code: CodeInvalidSubsection, Line: "[remote \"origin", LineNumber: 9, Column: 16, Offset: 120},
+	}
+	if len(errs) != len(want) {
+		t.Fatalf("ParseWith() returned %d errors, want %d: %v", len(errs), len(want), errs)
+	}
+	for i := range want {
+		if *errs[i] != want[i] {
+			t.Errorf("ParseWith() error = %+v, want %+v", *errs[i], want[i])
+		}
+	}
+	if !errors.Is(err, ErrInvalidSubsection) {
+		t.Errorf("errors.Is(%v, %v) = false, want true", err, ErrInvalidSubsection)
+	}
+
+	keys := make([]string, 0)
+	for _, key := range gc.Keys() {
+		keys = append(keys, key.String())
+	}
+	if wantKeys := []string{"core.bare", "user.email"}; !reflect.DeepEqual(keys, wantKeys) {
+		t.Errorf("GitConfig.Keys() = %v, want %v", keys, wantKeys)
+	}
+
+	_, err = ParseWith([]byte(config), ParseOptions{})
+	pe := new(ParseError)
+	if !errors.As(err, &pe) || pe.LineNumber != 3 {
+		t.Errorf("ParseWith() error = %v, want a *ParseError at line %d", err, 3)
+	}
+}
+
 func TestParsedValue(t *testing.T) {
 	configContent, err := os.ReadFile("configsamples/good.gitconfig")
 	if err != nil {
@@ -134,3 +202,151 @@ func join(vals []Value) string {
 
 	return sb.String()
 }
+
+func TestParse_VariableWithoutValue(t *testing.T) {
+	const config = "[core]\n" +
+		"\tbare\n" +
+		"\tempty =\n" +
+		"\tlast"
+
+	gc, err := Parse([]byte(config))
+	if err != nil {
+		t.Fatalf("Parse() error = %v, want %v", err, nil)
+	}
+
+	tests := []struct {
+		key          string
+		wantHasValue bool
+		wantBool     bool
+	}{
+		{key: "core.bare", wantBool: true},
+		{key: "core.empty", wantHasValue: true},
+		{key: "core.last", wantBool: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			got, err := gc.Get(tt.key)
+			if err != nil {
+				t.Fatalf("GitConfig.Get() error = %v, want %v", err, nil)
+			}
+			if got.HasValue() != tt.wantHasValue {
+				t.Errorf("Value.HasValue() = %v, want %v", got.HasValue(), tt.wantHasValue)
+			}
+			if got.String() != "" {
+				t.Errorf("Value.String() = %q, want %q", got.String(), "")
+			}
+			b, err := got.Bool()
+			if err != nil || b != tt.wantBool {
+				t.Errorf("Value.Bool() = (%v, %v), want (%v, %v)", b, err, tt.wantBool, nil)
+			}
+		})
+	}
+
+	err = gc.Set("core.empty", "false")
+	if err != nil {
+		t.Fatalf("GitConfig.Set() error = %v, want %v", err, nil)
+	}
+	filePath := filepath.Join(t.TempDir(), "config")
+	err = gc.Save(filePath)
+	if err != nil {
+		t.Fatalf("GitConfig.Save() error = %v, want %v", err, nil)
+	}
+	got, err := os.ReadFile(filePath)
+	if err != nil {
+		t.Fatalf("os.ReadFile(%s) error = %v, want %v", filePath, err, nil)
+	}
+	want := "[core]\n\tbare\n\tempty = false\n\tlast"
+	if string(got) != want {
+		t.Errorf("GitConfig.Save() wrote %q, want %q", got, want)
+	}
+}
+
+func TestParse_Subsection(t *testing.T) {
+	tests := []struct {
+		name    string
+		header  string
+		want    Section
+		wantErr error
+	}{
+		{name: "plain", header: `[remote "origin"]`, want: Section{Name: "remote", Subsection: "origin"}},
+		{name: "escaped quote", header: `[remote "a\"b"]`, want: Section{Name: "remote", Subsection: `a"b`}},
+		{name: "escaped backslash", header: `[remote "a\\b"]`, want: Section{Name: "remote", Subsection: `a\b`}},
+		{name: "other escape", header: `[remote "a\nb"]`, want: Section{Name: "remote", Subsection: "anb"}},
+		{name: "spaces before quote", header: "[remote \t \"origin\"]", want: Section{Name: "remote", Subsection: "origin"}},
+		{name: "empty", header: `[remote ""]`, want: Section{Name: "remote", emptySubsection: true}},
+		{name: "unescaped quote", header: `[remote "a"b"]`, wantErr: ErrInvalidLine},
+		{name: "unclosed quote", header: `[remote "origin]`, wantErr: ErrInvalidSubsection},
+		{name: "escaped line end", header: "[remote \"a\\\nb\"]", wantErr: ErrInvalidSubsection},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gc, err := Parse([]byte(tt.header + "\n\turl = x\n"))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Parse() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got := gc.lines.front().val.section; got != tt.want {
+				t.Errorf("Parse() section = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParse_VariableAfterHeader(t *testing.T) {
+	const config = "[core] bare = true\n[user]\tname = x # me\n"
+	gc, err := Parse([]byte(config))
+	if err != nil {
+		t.Fatalf("Parse() error = %v, want %v", err, nil)
+	}
+	for key, want := range map[string]string{"core.bare": "true", "user.name": "x"} {
+		got, err := gc.Get(key)
+		if err != nil || got.String() != want {
+			t.Errorf("GitConfig.Get(%s) = (%q, %v), want (%q, %v)", key, got, err, want, nil)
+		}
+	}
+	if got, _ := gc.MarshalText(); string(got) != config {
+		t.Errorf("GitConfig.MarshalText() = %q, want %q", got, config)
+	}
+
+	err = gc.Unset("core.bare")
+	if err != nil {
+		t.Fatalf("GitConfig.Unset() error = %v, want %v", err, nil)
+	}
+	want := "[core]\n[user]\tname = x # me\n"
+	if got, _ := gc.MarshalText(); string(got) != want {
+		t.Errorf("GitConfig.MarshalText() = %q, want %q", got, want)
+	}
+
+	_, err = Parse([]byte("[core] !bare\n"))
+	if !errors.Is(err, ErrInvalidLine) {
+		t.Errorf("Parse() error = %v, want %v", err, ErrInvalidLine)
+	}
+}
+
+func TestParse_EmptySubsection(t *testing.T) {
+	gc, err := Parse([]byte("[remote]\n\turl = a\n[remote \"\"]\n\turl = b\n"))
+	if err != nil {
+		t.Fatalf("Parse() error = %v, want %v", err, nil)
+	}
+	for key, want := range map[string]string{"remote.url": "a", "remote..url": "b"} {
+		got, err := gc.Get(key)
+		if err != nil || got.String() != want {
+			t.Errorf("GitConfig.Get(%s) = (%q, %v), want (%q, %v)", key, got, err, want, nil)
+		}
+	}
+	want := []Key{{Section{Name: "remote"}, "url"}, {Section{Name: "remote", emptySubsection: true}, "url"}}
+	if got := gc.Keys(); !reflect.DeepEqual(got, want) {
+		t.Errorf("GitConfig.Keys() = %v, want %v", got, want)
+	}
+}
+
+func TestParse_UnclosedQuote(t *testing.T) {
+	for _, variable := range []string{`name = "value`, `name = value"`, "name = \"a \\\n b"} {
+		_, err := Parse([]byte("[core]\n\t" + variable + "\n"))
+		if !errors.Is(err, ErrInvalidVariableValue) {
+			t.Errorf("Parse(%q) error = %v, want %v", variable, err, ErrInvalidVariableValue)
+		}
+	}
+}
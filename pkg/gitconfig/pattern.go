@@ -0,0 +1,172 @@
+package gitconfig
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// valuePattern selects values like the value-pattern argument of git config: an
+// extended regular expression which is negated by a leading '!'. An empty
+// pattern matches every value.
+type valuePattern struct {
+	re     *regexp.Regexp
+	negate bool
+}
+
+func compileValuePattern(pattern string) (valuePattern, error) {
+	var p valuePattern
+	if len(pattern) == 0 {
+		return p, nil
+	}
+
+	pattern, p.negate = strings.CutPrefix(pattern, "!")
+	re, err := regexp.CompilePOSIX(pattern)
+	if err != nil {
+		return valuePattern{}, fmt.Errorf("%w: %s", ErrInvalidPattern, err.Error())
+	}
+	p.re = re
+
+	return p, nil
+}
+
+// match reports whether val is selected by p. A variable without
+// a value only matches a negated pattern.
+func (p valuePattern) match(val Value) bool {
+	if p.re == nil {
+		return true
+	}
+	return p.negate != (val.HasValue() && p.re.MatchString(val.String()))
+}
+
+// FixedValue returns a value pattern that only matches s itself, like
+// the --fixed-value option of git config.
+func FixedValue(s string) string {
+	return "^" + regexp.QuoteMeta(s) + "$"
+}
+
+// GetRegexp returns every variable whose key matches the regular expression
+// keyPattern, like "git config --get-regexp". Keys are matched in their
+// canonical form, with section and variable names in lower case
+// (e.g. "remote.origin.url").
+func (g GitConfig) GetRegexp(keyPattern string) ([]Entry, error) {
+	re, err := regexp.CompilePOSIX(keyPattern)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidPattern, err.Error())
+	}
+
+	var entries []Entry
+	for e := g.lines.front(); e != nil; e = e.next {
+		if e.val.typ != variable {
+			continue
+		}
+		key := Key{e.val.section.canonical(), e.val.name.canonical()}
+		if !re.MatchString(key.String()) {
+			continue
+		}
+		entries = append(entries, Entry{
+			Key:    Key{e.val.section, e.val.name},
+			Value:  e.val.value,
+			Origin: Origin{File: e.val.file, Line: e.val.lineNumber},
+		})
+	}
+
+	return entries, nil
+}
+
+// ReplaceAll replaces every value of key matching valuePattern with val, like
+// "git config --replace-all". The new value takes the place of the last matching
+// value, the others are removed. If no value matches, val is added to key.
+// See FixedValue() to match a value literally.
+func (g *GitConfig) ReplaceAll(key string, val interface{}, valuePattern string) error {
+	values, err := g.isValidValues(val)
+	if err != nil {
+		return err
+	}
+
+	section, varName, err := g.splitKey(key)
+	if err != nil {
+		return err
+	}
+
+	p, err := compileValuePattern(valuePattern)
+	if err != nil {
+		return err
+	}
+
+	matched := g.matching(section, varName, p)
+	if len(matched) == 0 {
+		g.add(section, varName, values...)
+		return nil
+	}
+
+	last := matched[len(matched)-1]
+	if last.val.value != values[0] {
+		last.val.setValue(values[0])
+	}
+	g.remove(section, varName, matched[:len(matched)-1])
+
+	return nil
+}
+
+// UnsetAll removes every value of key matching valuePattern, like
+// "git config --unset-all". It returns ErrKeyNotFound if no value matches.
+// See FixedValue() to match a value literally.
+func (g *GitConfig) UnsetAll(key string, valuePattern string) error {
+	section, varName, err := g.splitKey(key)
+	if err != nil {
+		return err
+	}
+
+	p, err := compileValuePattern(valuePattern)
+	if err != nil {
+		return err
+	}
+
+	matched := g.matching(section, varName, p)
+	if len(matched) == 0 {
+		return ErrKeyNotFound
+	}
+	g.remove(section, varName, matched)
+
+	return nil
+}
+
+// matching returns the variable lines of a given key whose value matches p.
+func (g GitConfig) matching(section Section, key VariableName, p valuePattern) []*node[syntaxNode] {
+	var matched []*node[syntaxNode]
+	for _, e := range g.nodes(section, key) {
+		if p.match(e.val.value) {
+			matched = append(matched, e)
+		}
+	}
+	return matched
+}
+
+// remove removes the given variable lines of a key.
+func (g *GitConfig) remove(section Section, key VariableName, lines []*node[syntaxNode]) {
+	if len(lines) == 0 {
+		return
+	}
+
+	nodes := g.nodes(section, key)
+	kept := make([]*node[syntaxNode], 0, len(nodes))
+	for _, e := range nodes {
+		if slices.Contains(lines, e) {
+			g.removeLine(e)
+			continue
+		}
+		kept = append(kept, e)
+	}
+
+	sec, name := section.canonical(), key.canonical()
+	switch {
+	case len(kept) > 0:
+		g.data.mustGet(sec).put(name, kept)
+	case g.data.mustGet(sec).len() == 1:
+		g.data.remove(sec)
+	default:
+		g.data.mustGet(sec).remove(name)
+	}
+}
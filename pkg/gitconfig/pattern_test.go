@@ -0,0 +1,175 @@
+package gitconfig
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+const patternConfig = "[include]\n" +
+	"\tpath = ~/.config/git-sw/a/.gitconfig\n" +
+	"\tpath = work.inc\n" +
+	"\tpath = ~/.config/git-sw/b/.gitconfig\n" +
+	"\tpath\n" +
+	"[Remote \"origin\"]\n" +
+	"\turl = git@github.com:me/repo.git\n" +
+	"\tpushURL = git@github.com:me/push.git\n"
+
+func TestGitConfig_GetRegexp(t *testing.T) {
+	gc, err := Parse([]byte(patternConfig))
+	if err != nil {
+		t.Fatalf("Parse() error = %v, want %v", err, nil)
+	}
+
+	tests := []struct {
+		name    string
+		pattern string
+		want    []Key
+		wantErr error
+	}{
+		{
+			name:    "Canonical Key",
+			pattern: `^remote\.origin\.pushurl$`,
+			want:    []Key{{Section{Name: "Remote", Subsection: "origin"}, "pushURL"}},
+		},
+		{
+			name:    "Partial Match",
+			pattern: "url",
+			want:    []Key{{Section{Name: "Remote", Subsection: "origin"}, "url"}, {Section{Name: "Remote", Subsection: "origin"}, "pushURL"}},
+		},
+		{
+			name:    "No Match",
+			pattern: "^user",
+		},
+		{
+			name:    "Invalid Pattern",
+			pattern: "remote.(",
+			wantErr: ErrInvalidPattern,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, err := gc.GetRegexp(tt.pattern)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("GitConfig.GetRegexp() error = %v, want %v", err, tt.wantErr)
+			}
+			var got []Key
+			for _, e := range entries {
+				got = append(got, e.Key)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GitConfig.GetRegexp() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGitConfig_ValuePattern(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(g *GitConfig) error
+		want    string
+		wantErr error
+	}{
+		{
+			name: "Replace All Matching",
+			modify: func(g *GitConfig) error {
+				return g.ReplaceAll("include.path", "~/.config/git-sw/c/.gitconfig", `git-sw.*\.gitconfig$`)
+			},
+			want: "[include]\n" +
+				"\tpath = work.inc\n" +
+				"\tpath = ~/.config/git-sw/c/.gitconfig\n" +
+				"\tpath\n" +
+				"[Remote \"origin\"]\n" +
+				"\turl = git@github.com:me/repo.git\n" +
+				"\tpushURL = git@github.com:me/push.git\n",
+		},
+		{
+			name: "Replace Without Match Adds Value",
+			modify: func(g *GitConfig) error {
+				return g.ReplaceAll("remote.origin.fetch", "+refs/heads/*:refs/remotes/origin/*", "^nothing$")
+			},
+			want: patternConfig + "\tfetch = +refs/heads/*:refs/remotes/origin/*\n",
+		},
+		{
+			name: "Replace Negated Pattern",
+			modify: func(g *GitConfig) error {
+				return g.ReplaceAll("include.path", "only.inc", "!git-sw")
+			},
+			want: "[include]\n" +
+				"\tpath = ~/.config/git-sw/a/.gitconfig\n" +
+				"\tpath = ~/.config/git-sw/b/.gitconfig\n" +
+				"\tpath = only.inc\n" +
+				"[Remote \"origin\"]\n" +
+				"\turl = git@github.com:me/repo.git\n" +
+				"\tpushURL = git@github.com:me/push.git\n",
+		},
+		{
+			name: "Unset All Matching",
+			modify: func(g *GitConfig) error {
+				return g.UnsetAll("include.path", "git-sw")
+			},
+			want: "[include]\n" +
+				"\tpath = work.inc\n" +
+				"\tpath\n" +
+				"[Remote \"origin\"]\n" +
+				"\turl = git@github.com:me/repo.git\n" +
+				"\tpushURL = git@github.com:me/push.git\n",
+		},
+		{
+			name: "Unset All Fixed Value",
+			modify: func(g *GitConfig) error {
+				return g.UnsetAll("remote.origin.url", FixedValue("git@github.com:me/repo.git"))
+			},
+			want: "[include]\n" +
+				"\tpath = ~/.config/git-sw/a/.gitconfig\n" +
+				"\tpath = work.inc\n" +
+				"\tpath = ~/.config/git-sw/b/.gitconfig\n" +
+				"\tpath\n" +
+				"[Remote \"origin\"]\n" +
+				"\tpushURL = git@github.com:me/push.git\n",
+		},
+		{
+			name: "Unset All Without Pattern",
+			modify: func(g *GitConfig) error {
+				return g.UnsetAll("include.path", "")
+			},
+			want: "[include]\n" +
+				"[Remote \"origin\"]\n" +
+				"\turl = git@github.com:me/repo.git\n" +
+				"\tpushURL = git@github.com:me/push.git\n",
+		},
+		{
+			name: "Fixed Value Is Literal",
+			modify: func(g *GitConfig) error {
+				return g.UnsetAll("remote.origin.url", FixedValue("git@github.com:me/repo.gi."))
+			},
+			want:    patternConfig,
+			wantErr: ErrKeyNotFound,
+		},
+		{
+			name: "Invalid Value Pattern",
+			modify: func(g *GitConfig) error {
+				return g.ReplaceAll("include.path", "x", "[")
+			},
+			want:    patternConfig,
+			wantErr: ErrInvalidPattern,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gc, err := Parse([]byte(patternConfig))
+			if err != nil {
+				t.Fatalf("Parse() error = %v, want %v", err, nil)
+			}
+			err = tt.modify(gc)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("modify() error = %v, want %v", err, tt.wantErr)
+			}
+			got, _ := gc.MarshalText()
+			if string(got) != tt.want {
+				t.Errorf("GitConfig.MarshalText() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
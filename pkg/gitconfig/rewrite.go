@@ -0,0 +1,117 @@
+package gitconfig
+
+import "strings"
+
+// RewriteURL applies the url.<base>.insteadOf and url.<base>.pushInsteadOf
+// variables of g to url, and returns the URL git fetches from and the one it
+// pushes to for a remote whose url is url. Of the prefixes of url given by
+// insteadOf, the longest one is replaced by its base. The push URL is rewritten
+// the same way using pushInsteadOf, or is the fetch URL if no pushInsteadOf
+// matches.
+func (g GitConfig) RewriteURL(url string) (fetch, push string) {
+	var r urlRewriter
+	for e := g.lines.front(); e != nil; e = e.next {
+		r.read(e.val)
+	}
+	return r.rewriteOne(url)
+}
+
+// RewriteURL applies the url.<base>.insteadOf and url.<base>.pushInsteadOf
+// variables of every config in the set to url. See GitConfig.RewriteURL().
+func (cs ConfigSet) RewriteURL(url string) (fetch, push string) {
+	var r urlRewriter
+	for _, l := range cs.layers {
+		for e := l.config.lines.front(); e != nil; e = e.next {
+			r.read(e.val)
+		}
+	}
+	return r.rewriteOne(url)
+}
+
+// urlRewriter rewrites the URLs of remotes the way git does.
+type urlRewriter struct {
+	fetch, push urlRewrites
+}
+
+// read adds line to the rewrites if it is an insteadOf or a pushInsteadOf variable.
+func (r *urlRewriter) read(line syntaxNode) {
+	if line.typ != variable || strings.ToLower(line.section.Name) != "url" || !line.value.HasValue() {
+		return
+	}
+	switch line.name.canonical() {
+	case "insteadof":
+		r.fetch.add(line.section.Subsection, line.value.String())
+	case "pushinsteadof":
+		r.push.add(line.section.Subsection, line.value.String())
+	}
+}
+
+func (r urlRewriter) rewriteOne(url string) (fetch, push string) {
+	fetches, pushes := r.rewrite([]string{url}, nil)
+	return fetches[0], pushes[0]
+}
+
+// rewrite returns the fetch and push URLs of a remote whose remote.<name>.url
+// values are urls and remote.<name>.pushurl values are pushURLs. Like git,
+// pushInsteadOf is only used for remotes without a pushurl, and the push URLs
+// are the fetch URLs if none of urls is rewritten by it.
+func (r urlRewriter) rewrite(urls, pushURLs []string) (fetch, push []string) {
+	for _, url := range pushURLs {
+		push = append(push, r.fetch.rewrite(url))
+	}
+	for _, url := range urls {
+		if len(pushURLs) == 0 {
+			if rewritten, ok := r.push.apply(url); ok {
+				push = append(push, rewritten)
+			}
+		}
+		fetch = append(fetch, r.fetch.rewrite(url))
+	}
+	if len(push) == 0 {
+		push = fetch
+	}
+
+	return fetch, push
+}
+
+// urlRewrites maps bases to the URL prefixes they replace, in the order the bases
+// first appear.
+type urlRewrites struct {
+	bases    []string
+	prefixes [][]string
+}
+
+func (r *urlRewrites) add(base, prefix string) {
+	for i := range r.bases {
+		if r.bases[i] == base {
+			r.prefixes[i] = append(r.prefixes[i], prefix)
+			return
+		}
+	}
+	r.bases = append(r.bases, base)
+	r.prefixes = append(r.prefixes, []string{prefix})
+}
+
+// apply replaces the longest prefix of url found in r with its base. If two
+// prefixes are as long, the first one is used. ok is false if no prefix matches.
+func (r urlRewrites) apply(url string) (rewritten string, ok bool) {
+	base, longest := -1, 0
+	for i := range r.bases {
+		for _, prefix := range r.prefixes[i] {
+			if strings.HasPrefix(url, prefix) && (base < 0 || len(prefix) > longest) {
+				base, longest = i, len(prefix)
+			}
+		}
+	}
+	if base < 0 {
+		return url, false
+	}
+
+	return r.bases[base] + url[longest:], true
+}
+
+// rewrite is like apply, but returns url as is if no prefix matches.
+func (r urlRewrites) rewrite(url string) string {
+	rewritten, _ := r.apply(url)
+	return rewritten
+}
@@ -0,0 +1,150 @@
+package gitconfig
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+const rewriteConfig = `[url "git@github-work:"]
+	insteadOf = https://github.com/work/
+	insteadOf = gh-work:
+[url "git@github.com:"]
+	insteadOf = https://github.com/
+	pushInsteadOf = https://github.com/
+[url "https://mirror.example.com/"]
+	insteadOf = https://example.com/
+[url "ssh://push.example.com/"]
+	pushInsteadOf = https://example.com/
+	pushInsteadOf = https://mirror.example.com/
+[url "first:"]
+	insteadOf = same:
+[url "second:"]
+	insteadOf = same:
+[url "bare:"]
+	insteadOf
+`
+
+func TestGitConfig_RewriteURL(t *testing.T) {
+	gc, err := Parse([]byte(rewriteConfig))
+	if err != nil {
+		t.Fatalf("Parse() error = %v, want %v", err, nil)
+	}
+
+	tests := []struct {
+		url       string
+		wantFetch string
+		wantPush  string
+	}{
+		{url: "https://github.com/me/repo.git", wantFetch: "git@github.com:me/repo.git", wantPush: "git@github.com:me/repo.git"},
+		{url: "https://github.com/work/repo.git", wantFetch: "git@github-work:repo.git", wantPush: "git@github.com:work/repo.git"},
+		{url: "gh-work:repo.git", wantFetch: "git@github-work:repo.git", wantPush: "git@github-work:repo.git"},
+		{url: "https://example.com/repo", wantFetch: "https://mirror.example.com/repo", wantPush: "ssh://push.example.com/repo"},
+		{url: "same:repo", wantFetch: "first:repo", wantPush: "first:repo"},
+		{url: "https://gitlab.com/repo", wantFetch: "https://gitlab.com/repo", wantPush: "https://gitlab.com/repo"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			fetch, push := gc.RewriteURL(tt.url)
+			if fetch != tt.wantFetch || push != tt.wantPush {
+				t.Errorf("RewriteURL() = (%q, %q), want (%q, %q)", fetch, push, tt.wantFetch, tt.wantPush)
+			}
+		})
+	}
+}
+
+func TestUrlRewriter_Rewrite(t *testing.T) {
+	gc, err := Parse([]byte(rewriteConfig))
+	if err != nil {
+		t.Fatalf("Parse() error = %v, want %v", err, nil)
+	}
+	var r urlRewriter
+	for e := gc.lines.front(); e != nil; e = e.next {
+		r.read(e.val)
+	}
+
+	tests := []struct {
+		name      string
+		urls      []string
+		pushURLs  []string
+		wantFetch []string
+		wantPush  []string
+	}{
+		{
+			name:      "pushurl is rewritten with insteadOf",
+			urls:      []string{"https://github.com/a"},
+			pushURLs:  []string{"https://example.com/a"},
+			wantFetch: []string{"git@github.com:a"},
+			wantPush:  []string{"https://mirror.example.com/a"},
+		},
+		{
+			name:      "only urls rewritten with pushInsteadOf are pushed to",
+			urls:      []string{"https://gitlab.com/a", "https://example.com/a"},
+			wantFetch: []string{"https://gitlab.com/a", "https://mirror.example.com/a"},
+			wantPush:  []string{"ssh://push.example.com/a"},
+		},
+		{
+			name:      "no pushInsteadOf",
+			urls:      []string{"https://gitlab.com/a", "gh-work:a"},
+			wantFetch: []string{"https://gitlab.com/a", "git@github-work:a"},
+			wantPush:  []string{"https://gitlab.com/a", "git@github-work:a"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fetch, push := r.rewrite(tt.urls, tt.pushURLs)
+			if strings.Join(fetch, " ") != strings.Join(tt.wantFetch, " ") || strings.Join(push, " ") != strings.Join(tt.wantPush, " ") {
+				t.Errorf("rewrite() = (%q, %q), want (%q, %q)", fetch, push, tt.wantFetch, tt.wantPush)
+			}
+		})
+	}
+}
+
+func TestGitConfig_RewriteURL_Git(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping comparison with git in short mode")
+	}
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found")
+	}
+
+	gc, err := Parse([]byte(rewriteConfig))
+	if err != nil {
+		t.Fatalf("Parse() error = %v, want %v", err, nil)
+	}
+	dir := t.TempDir()
+	git := func(args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(), "GIT_CONFIG_NOSYSTEM=1", "GIT_CONFIG_GLOBAL=/dev/null")
+		out, err := cmd.Output()
+		if err != nil {
+			t.Fatalf("git %s error = %v", strings.Join(args, " "), err)
+		}
+		return strings.TrimSuffix(string(out), "\n")
+	}
+	git("init", "-q")
+	local, err := os.ReadFile(dir + "/.git/config")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, url := range []string{"https://github.com/me/repo.git", "https://github.com/work/repo.git", "gh-work:repo.git", "https://example.com/repo", "same:repo"} {
+		config := string(local) + strings.ReplaceAll(rewriteConfig, "\tinsteadOf\n", "") + "[remote \"origin\"]\n\turl = " + url + "\n"
+		if err := os.WriteFile(dir+"/.git/config", []byte(config), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		fetch, push := gc.RewriteURL(url)
+		if want := git("remote", "get-url", "origin"); fetch != want {
+			t.Errorf("RewriteURL(%q) fetch = %q, want %q", url, fetch, want)
+		}
+		if want := git("remote", "get-url", "--push", "origin"); push != want {
+			t.Errorf("RewriteURL(%q) push = %q, want %q", url, push, want)
+		}
+	}
+}
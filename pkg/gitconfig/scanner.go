@@ -0,0 +1,218 @@
+package gitconfig
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+)
+
+// TokenType is the kind of a Token.
+type TokenType int
+
+const (
+	_ TokenType = iota
+	// TokenSection is a section header, e.g. `[remote "origin"]`.
+	TokenSection
+	// TokenKey is the name of a variable.
+	TokenKey
+	// TokenValue is the value of a variable, as written in the file. A variable
+	// without '=' (e.g. "[core] bare") has no TokenValue.
+	TokenValue
+	// TokenComment is a comment, either on its own line or following a section header or a value.
+	TokenComment
+	// TokenBlank is a line with nothing but whitespace.
+	TokenBlank
+)
+
+var tokenTypeString = []string{
+	"unknown",
+	"section",
+	"key",
+	"value",
+	"comment",
+	"blank",
+}
+
+func (tt TokenType) String() string {
+	if tt < 0 || int(tt) >= len(tokenTypeString) {
+		return tokenTypeString[0]
+	}
+	return tokenTypeString[tt]
+}
+
+// Position is a location in a config file.
+type Position struct {
+	Offset int // byte offset, starting at 0
+	Line   int // line number, starting at 1
+	Column int // byte offset in the line, starting at 1
+}
+
+// Token is a part of a config file read by Scanner.
+type Token struct {
+	Type  TokenType
+	Raw   string   // text of the token as it's written in the file
+	Start Position // position of the first byte of the token
+	End   Position // position right after the last byte of the token
+	// Section is the section the token belongs to, or the section
+	// declared by the header of a TokenSection.
+	Section Section
+	// Name is the variable name of a TokenKey or TokenValue.
+	Name VariableName
+	// Value is the value of a TokenValue, see Value.String() to decode it.
+	Value Value
+}
+
+// Scanner reads a config file token by token, without keeping more than
+// the current line in memory. It uses the same rules as Parse().
+//
+//	s := gitconfig.NewScanner(r)
+//	for s.Scan() {
+//		tok := s.Token()
+//		...
+//	}
+//	if err := s.Err(); err != nil {
+//		...
+//	}
+type Scanner struct {
+	r       *bufio.Reader
+	buf     []byte // the lines that haven't been tokenized yet
+	start   int    // position in buf of what hasn't been tokenized, when a line is tokenized in parts
+	eof     bool
+	offset  int // offset of buf in the file
+	line    int // line number of buf in the file
+	section Section
+	tokens  []Token // tokens of the current line
+	tok     Token
+	err     error
+}
+
+// NewScanner returns a Scanner reading from r.
+func NewScanner(r io.Reader) *Scanner {
+	return &Scanner{
+		r:    bufio.NewReader(r),
+		line: 1,
+	}
+}
+
+// Scan advances to the next token, which is then available through Token().
+// It returns false at the end of the input or if an error occurred, see Err().
+func (s *Scanner) Scan() bool {
+	for len(s.tokens) == 0 {
+		if s.err != nil {
+			return false
+		}
+		s.scanLine()
+	}
+
+	s.tok, s.tokens = s.tokens[0], s.tokens[1:]
+	return true
+}
+
+// Token returns the token read by the last call to Scan().
+func (s *Scanner) Token() Token {
+	return s.tok
+}
+
+// Err returns the first error encountered by the Scanner. Like Parse(), syntax
+// errors are returned as a *ParseError. Reaching the end of the input isn't an error.
+func (s *Scanner) Err() error {
+	if errors.Is(s.err, io.EOF) {
+		return nil
+	}
+	return s.err
+}
+
+// readLine appends the next line of the input to buf.
+func (s *Scanner) readLine() {
+	line, err := s.r.ReadBytes('\n')
+	s.buf = append(s.buf, line...)
+	if err != nil {
+		s.eof = true
+		if !errors.Is(err, io.EOF) {
+			s.err = err
+		}
+	}
+}
+
+// scanLine tokenizes the next line, with its continuations.
+func (s *Scanner) scanLine() {
+	if len(s.buf) == 0 {
+		if s.eof {
+			s.err = io.EOF
+			return
+		}
+		s.readLine()
+		return
+	}
+
+	c := new(configFile)
+	c.init(s.buf)
+	c.off, c.cline = s.start, s.line
+	line, err := c.nextLine(s.section)
+	if c.off == c.n && !s.eof {
+		// the line may continue past what has been read so far
+		s.readLine()
+		return
+	}
+	if err != nil {
+		if pe := new(ParseError); errors.As(err, &pe) {
+			pe.Offset += s.offset
+		}
+		s.err = err
+		return
+	}
+
+	switch line.typ {
+	case section:
+		s.section = line.section
+		s.emit(c, TokenSection, c.nameStart, c.nameEnd, line)
+	case variable:
+		s.emit(c, TokenKey, c.nameStart, c.nameEnd, line)
+		if c.valueStart >= 0 {
+			s.emit(c, TokenValue, c.valueStart, c.valueEnd, line)
+		}
+	case blank, end:
+		s.emit(c, TokenBlank, 0, c.lineEnd, line)
+	}
+	if c.commentStart >= 0 {
+		s.emit(c, TokenComment, c.commentStart, c.lineEnd, line)
+	}
+
+	if c.off < c.n && c.data[c.off-1] != '\n' {
+		// the header is followed by a variable on the same line
+		s.start = c.off
+		return
+	}
+	s.offset += c.off
+	s.line = c.cline
+	s.buf, s.start = s.buf[c.off:], 0
+}
+
+func (s *Scanner) emit(c *configFile, typ TokenType, start, end int, line syntaxNode) {
+	tok := Token{
+		Type:    typ,
+		Raw:     string(c.data[start:end]),
+		Start:   s.position(start),
+		End:     s.position(end),
+		Section: s.section,
+	}
+	if typ == TokenKey || typ == TokenValue {
+		tok.Name = line.name
+	}
+	if typ == TokenValue {
+		tok.Value = line.value
+	}
+	s.tokens = append(s.tokens, tok)
+}
+
+// position returns the position of the byte at off in buf.
+func (s *Scanner) position(off int) Position {
+	before := s.buf[:off]
+	lineStart := bytes.LastIndexByte(before, '\n') + 1
+	return Position{
+		Offset: s.offset + off,
+		Line:   s.line + bytes.Count(before, []byte{'\n'}),
+		Column: off - lineStart + 1,
+	}
+}
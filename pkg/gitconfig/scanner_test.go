@@ -0,0 +1,139 @@
+package gitconfig
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestScanner(t *testing.T) {
+	config := "# top\n" +
+		"[remote \"origin\"] ; header comment\r\n" +
+		"\n" +
+		"  url = \"git@github.com:me/repo.git\" # trailing\n" +
+		"\tfetch = a \\\n" +
+		"b\n" +
+		"\tmirror\n" +
+		"\tempty =\n" +
+		"   "
+
+	origin := Section{Name: "remote", Subsection: "origin"}
+	want := []Token{
+		{Type: TokenComment, Raw: "# top", Start: Position{0, 1, 1}, End: Position{5, 1, 6}},
+		{Type: TokenSection, Raw: `[remote "origin"]`, Start: Position{6, 2, 1}, End: Position{23, 2, 18}, Section: origin},
+		{Type: TokenComment, Raw: "; header comment", Start: Position{24, 2, 19}, End: Position{40, 2, 35}, Section: origin},
+		{Type: TokenBlank, Raw: "", Start: Position{42, 3, 1}, End: Position{42, 3, 1}, Section: origin},
+		{Type: TokenKey, Raw: "url", Start: Position{45, 4, 3}, End: Position{48, 4, 6}, Section: origin, Name: "url"},
+		{Type: TokenValue, Raw: `"git@github.com:me/repo.git"`, Start: Position{51, 4, 9}, End: Position{79, 4, 37}, Section: origin, Name: "url", Value: Value{`"git@github.com:me/repo.git"`}},
+		{Type: TokenComment, Raw: "# trailing", Start: Position{80, 4, 38}, End: Position{90, 4, 48}, Section: origin},
+		{Type: TokenKey, Raw: "fetch", Start: Position{92, 5, 2}, End: Position{97, 5, 7}, Section: origin, Name: "fetch"},
+		{Type: TokenValue, Raw: "a \\\nb", Start: Position{100, 5, 10}, End: Position{105, 6, 2}, Section: origin, Name: "fetch", Value: Value{"a b"}},
+		{Type: TokenKey, Raw: "mirror", Start: Position{107, 7, 2}, End: Position{113, 7, 8}, Section: origin, Name: "mirror"},
+		{Type: TokenKey, Raw: "empty", Start: Position{115, 8, 2}, End: Position{120, 8, 7}, Section: origin, Name: "empty"},
+		{Type: TokenValue, Raw: "", Start: Position{122, 8, 9}, End: Position{122, 8, 9}, Section: origin, Name: "empty", Value: Value{""}},
+		{Type: TokenBlank, Raw: "   ", Start: Position{123, 9, 1}, End: Position{126, 9, 4}, Section: origin},
+	}
+
+	s := NewScanner(iotest.OneByteReader(strings.NewReader(config)))
+	var got []Token
+	for s.Scan() {
+		got = append(got, s.Token())
+	}
+	if err := s.Err(); err != nil {
+		t.Fatalf("Scanner.Err() = %v, want %v", err, nil)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Scanner.Scan() read %d tokens, want %d: %+v", len(got), len(want), got)
+	}
+	for i := range want {
+		if !reflect.DeepEqual(got[i], want[i]) {
+			t.Errorf("Scanner.Token() = %+v, want %+v", got[i], want[i])
+		}
+		if config[got[i].Start.Offset:got[i].End.Offset] != got[i].Raw {
+			t.Errorf("Token.Raw = %q, want the text between its offsets %q", got[i].Raw, config[got[i].Start.Offset:got[i].End.Offset])
+		}
+	}
+}
+
+func TestScanner_VariableAfterHeader(t *testing.T) {
+	core := Section{Name: "core"}
+	want := []Token{
+		{Type: TokenSection, Raw: "[core]", Start: Position{0, 1, 1}, End: Position{6, 1, 7}, Section: core},
+		{Type: TokenKey, Raw: "bare", Start: Position{7, 1, 8}, End: Position{11, 1, 12}, Section: core, Name: "bare"},
+		{Type: TokenValue, Raw: "true", Start: Position{14, 1, 15}, End: Position{18, 1, 19}, Section: core, Name: "bare", Value: Value{"true"}},
+		{Type: TokenComment, Raw: "# c", Start: Position{19, 1, 20}, End: Position{22, 1, 23}, Section: core},
+		{Type: TokenKey, Raw: "name", Start: Position{24, 2, 2}, End: Position{28, 2, 6}, Section: core, Name: "name"},
+	}
+
+	s := NewScanner(iotest.OneByteReader(strings.NewReader("[core] bare = true # c\n\tname\n")))
+	var got []Token
+	for s.Scan() {
+		got = append(got, s.Token())
+	}
+	if err := s.Err(); err != nil {
+		t.Fatalf("Scanner.Err() = %v, want %v", err, nil)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Scanner.Scan() read %+v, want %+v", got, want)
+	}
+}
+
+func TestScanner_Error(t *testing.T) {
+	s := NewScanner(strings.NewReader("[core]\n\tbare = true\n\t1bare = false\n[user]\n"))
+	var n int
+	for s.Scan() {
+		n++
+	}
+	if n != 3 {
+		t.Errorf("Scanner.Scan() read %d tokens, want %d", n, 3)
+	}
+
+	var pe *ParseError
+	if !errors.As(s.Err(), &pe) || pe.LineNumber != 3 || pe.Offset != 21 || !errors.Is(pe.Err, ErrInvalidLine) {
+		t.Errorf("Scanner.Err() = %v, want a *ParseError at line %d", s.Err(), 3)
+	}
+}
+
+func TestScanner_MatchesParse(t *testing.T) {
+	samples, err := filepath.Glob("configsamples/*.gitconfig")
+	if err != nil {
+		t.Fatalf("filepath.Glob() error = %v, want %v", err, nil)
+	}
+	for _, sample := range samples {
+		t.Run(filepath.Base(sample), func(t *testing.T) {
+			content, err := os.ReadFile(sample)
+			if err != nil {
+				t.Fatalf("os.ReadFile(%s) error = %v, want %v", sample, err, nil)
+			}
+			gc, parseErr := Parse(content)
+
+			var got []Key
+			s := NewScanner(strings.NewReader(string(content)))
+			for s.Scan() {
+				if tok := s.Token(); tok.Type == TokenKey {
+					got = append(got, Key{tok.Section, tok.Name})
+				}
+			}
+			if (parseErr == nil) != (s.Err() == nil) {
+				t.Fatalf("Scanner.Err() = %v, want %v", s.Err(), parseErr)
+			}
+			if parseErr != nil {
+				return
+			}
+
+			var want []Key
+			for e := gc.lines.front(); e != nil; e = e.next {
+				if e.val.typ == variable {
+					want = append(want, Key{e.val.section, e.val.name})
+				}
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("Scanner keys = %v, want %v", got, want)
+			}
+		})
+	}
+}
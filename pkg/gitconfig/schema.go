@@ -0,0 +1,360 @@
+package gitconfig
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// KeyType is the type of the value of a known key.
+type KeyType int
+
+const (
+	_ KeyType = iota
+	// TypeString accepts any value.
+	TypeString
+	// TypeBool accepts the values Value.Bool() accepts.
+	TypeBool
+	// TypeInt accepts the values Value.Int() accepts.
+	TypeInt
+	// TypePath accepts the values Value.Path() accepts.
+	TypePath
+	// TypeColor accepts the values Value.Color() accepts.
+	TypeColor
+	// TypeExpiryDate accepts the values Value.ExpiryDate() accepts.
+	TypeExpiryDate
+)
+
+var keyTypeString = []string{
+	"unknown",
+	"string",
+	"bool",
+	"int",
+	"path",
+	"color",
+	"expiry-date",
+}
+
+func (kt KeyType) String() string {
+	if kt < 0 || int(kt) >= len(keyTypeString) {
+		return keyTypeString[0]
+	}
+	return keyTypeString[kt]
+}
+
+// KeySchema describes the values of a known key.
+type KeySchema struct {
+	// Key is the key, where "*" stands for any subsection or any variable
+	// name, e.g. "url.*.insteadOf" or "alias.*".
+	Key  string
+	Type KeyType
+	// Values lists the allowed values. If Type isn't TypeString, values of
+	// Type are allowed as well, e.g. core.autocrlf is a bool or "input".
+	Values []string
+}
+
+// validate reports whether val is a valid value for the key.
+func (ks KeySchema) validate(val Value) error {
+	if slices.Contains(ks.Values, val.String()) && val.HasValue() {
+		return nil
+	}
+
+	var err error
+	switch ks.Type {
+	case TypeBool:
+		_, err = val.Bool()
+	case TypeInt:
+		_, err = val.Int()
+	case TypePath:
+		_, err = val.Path()
+	case TypeColor:
+		_, err = val.Color()
+	case TypeExpiryDate:
+		_, err = val.ExpiryDate()
+	}
+	if ks.Type != TypeBool && !val.HasValue() {
+		err = ErrEmptyValue
+	}
+	if err == nil && ks.Type == TypeString && len(ks.Values) > 0 {
+		err = ErrInvalidEnum
+	}
+	switch {
+	case err == nil || len(ks.Values) == 0:
+	case ks.Type == TypeString:
+		err = fmt.Errorf("%w, want one of %s", err, strings.Join(ks.Values, ", "))
+	default:
+		err = fmt.Errorf("%w, want a %s or one of %s", err, ks.Type, strings.Join(ks.Values, ", "))
+	}
+
+	return err
+}
+
+// match reports whether the key matches the key of the schema.
+func (ks KeySchema) match(key Key) bool {
+	sec, name, ok := strings.Cut(ks.Key, ".")
+	if !ok {
+		return false
+	}
+	var sub string
+	i := strings.LastIndexByte(name, '.')
+	hasSub := i >= 0
+	if hasSub {
+		sub, name = name[:i], name[i+1:]
+	}
+
+	return strings.EqualFold(sec, key.Section.Name) &&
+		hasSub == key.Section.hasSubsection() && (sub == "*" || sub == key.Section.Subsection) &&
+		(name == "*" || strings.EqualFold(name, string(key.Name)))
+}
+
+// Schema is a registry of known keys, used to validate configs.
+type Schema struct {
+	keys []KeySchema
+}
+
+// NewSchema returns a schema made of keys.
+func NewSchema(keys ...KeySchema) *Schema {
+	s := new(Schema)
+	s.Register(keys...)
+	return s
+}
+
+// Register adds keys to the schema. A key overrides the keys registered before it.
+func (s *Schema) Register(keys ...KeySchema) {
+	s.keys = append(s.keys, keys...)
+}
+
+// Lookup returns the schema of key.
+func (s Schema) Lookup(key string) (KeySchema, bool) {
+	section, name, err := GitConfig{}.splitKey(key)
+	if err != nil {
+		return KeySchema{}, false
+	}
+	return s.lookup(Key{section, name})
+}
+
+func (s Schema) lookup(key Key) (KeySchema, bool) {
+	for i := len(s.keys) - 1; i >= 0; i-- {
+		if s.keys[i].match(key) {
+			return s.keys[i], true
+		}
+	}
+	return KeySchema{}, false
+}
+
+// ValidateOptions controls how ValidateWith checks a config.
+type ValidateOptions struct {
+	// UnknownKeys reports the keys that aren't in the schema, once each, with
+	// ErrUnknownKey. As a schema is rarely exhaustive, they're ignored by default.
+	UnknownKeys bool
+}
+
+// Validate checks every variable of g against the schema, ignoring the keys
+// that aren't in it. See Schema.ValidateWith().
+func (s Schema) Validate(g *GitConfig) error {
+	return s.ValidateWith(g, ValidateOptions{})
+}
+
+// ValidateWith checks every variable of g against the schema. The values that
+// aren't valid for their key are reported with the error of the Value method
+// of their type (e.g. ErrInvalidBool) or ErrInvalidEnum. It returns nil or a
+// ValidationErrors.
+func (s Schema) ValidateWith(g *GitConfig, opts ValidateOptions) error {
+	var errs ValidationErrors
+	unknown := make(map[Key]bool)
+	for e := g.lines.front(); e != nil; e = e.next {
+		line := e.val
+		if line.typ != variable {
+			continue
+		}
+		key := Key{line.section, line.name}
+		ks, ok := s.lookup(key)
+		if !ok {
+			if !opts.UnknownKeys {
+				continue
+			}
+			canonical := Key{key.Section.canonical(), key.Name.canonical()}
+			if !unknown[canonical] {
+				unknown[canonical] = true
+				errs = append(errs, &ValidationError{Err: ErrUnknownKey, Key: key.String(), LineNumber: line.lineNumber})
+			}
+			continue
+		}
+		err := ks.validate(line.value)
+		if err != nil {
+			errs = append(errs, &ValidationError{Err: err, Key: key.String(), Value: line.value.String(), LineNumber: line.lineNumber})
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// Validate checks every variable of g against DefaultSchema(). See Schema.Validate().
+func Validate(g *GitConfig) error {
+	return DefaultSchema().Validate(g)
+}
+
+// ValidateWith checks every variable of g against DefaultSchema(). See Schema.ValidateWith().
+func ValidateWith(g *GitConfig, opts ValidateOptions) error {
+	return DefaultSchema().ValidateWith(g, opts)
+}
+
+// DefaultSchema returns a schema of well-known git keys, it isn't meant to be
+// exhaustive. Every call returns a new schema, keys can be added to it with
+// Register().
+func DefaultSchema() *Schema {
+	return NewSchema(defaultKeys...)
+}
+
+var (
+	boolOrAlways = []string{"always"}
+	colorWhen    = []string{"auto", "always", "never"}
+	rebaseModes  = []string{"merges", "interactive"}
+)
+
+// defaultKeys are the keys of DefaultSchema(). Like git, the http keys can be
+// set for a URL, e.g. http.<url>.sslVerify.
+var defaultKeys = []KeySchema{
+	KeySchema{Key: "user.name", Type: TypeString},
+	KeySchema{Key: "user.email", Type: TypeString},
+	KeySchema{Key: "user.signingKey", Type: TypeString},
+	KeySchema{Key: "user.useConfigOnly", Type: TypeBool},
+	KeySchema{Key: "author.name", Type: TypeString},
+	KeySchema{Key: "author.email", Type: TypeString},
+	KeySchema{Key: "committer.name", Type: TypeString},
+	KeySchema{Key: "committer.email", Type: TypeString},
+
+	KeySchema{Key: "core.editor", Type: TypeString},
+	KeySchema{Key: "core.pager", Type: TypeString},
+	KeySchema{Key: "core.sshCommand", Type: TypeString},
+	KeySchema{Key: "core.whitespace", Type: TypeString},
+	KeySchema{Key: "core.excludesFile", Type: TypePath},
+	KeySchema{Key: "core.attributesFile", Type: TypePath},
+	KeySchema{Key: "core.hooksPath", Type: TypePath},
+	KeySchema{Key: "core.worktree", Type: TypePath},
+	KeySchema{Key: "core.bare", Type: TypeBool},
+	KeySchema{Key: "core.fileMode", Type: TypeBool},
+	KeySchema{Key: "core.ignoreCase", Type: TypeBool},
+	KeySchema{Key: "core.symlinks", Type: TypeBool},
+	KeySchema{Key: "core.quotePath", Type: TypeBool},
+	KeySchema{Key: "core.longPaths", Type: TypeBool},
+	KeySchema{Key: "core.logAllRefUpdates", Type: TypeBool, Values: boolOrAlways},
+	KeySchema{Key: "core.autocrlf", Type: TypeBool, Values: []string{"input"}},
+	KeySchema{Key: "core.safecrlf", Type: TypeBool, Values: []string{"warn"}},
+	KeySchema{Key: "core.eol", Type: TypeString, Values: []string{"lf", "crlf", "native"}},
+	KeySchema{Key: "core.compression", Type: TypeInt},
+	KeySchema{Key: "core.repositoryFormatVersion", Type: TypeInt},
+	KeySchema{Key: "core.abbrev", Type: TypeInt, Values: []string{"auto", "no"}},
+	KeySchema{Key: "core.fsmonitor", Type: TypeString},
+	KeySchema{Key: "core.untrackedCache", Type: TypeBool, Values: []string{"keep"}},
+	KeySchema{Key: "core.preloadIndex", Type: TypeBool},
+	KeySchema{Key: "core.commitGraph", Type: TypeBool},
+	KeySchema{Key: "core.trustctime", Type: TypeBool},
+	KeySchema{Key: "core.checkStat", Type: TypeString, Values: []string{"default", "minimal"}},
+	KeySchema{Key: "core.precomposeUnicode", Type: TypeBool},
+	KeySchema{Key: "core.protectNTFS", Type: TypeBool},
+	KeySchema{Key: "core.splitIndex", Type: TypeBool},
+	KeySchema{Key: "core.sparseCheckout", Type: TypeBool},
+	KeySchema{Key: "feature.manyFiles", Type: TypeBool},
+	KeySchema{Key: "feature.experimental", Type: TypeBool},
+
+	KeySchema{Key: "commit.gpgSign", Type: TypeBool},
+	KeySchema{Key: "commit.template", Type: TypePath},
+	KeySchema{Key: "commit.verbose", Type: TypeBool},
+	KeySchema{Key: "commit.cleanup", Type: TypeString, Values: []string{"strip", "whitespace", "verbatim", "scissors", "default"}},
+	KeySchema{Key: "tag.gpgSign", Type: TypeBool},
+	KeySchema{Key: "tag.forceSignAnnotated", Type: TypeBool},
+	KeySchema{Key: "gpg.program", Type: TypeString},
+	KeySchema{Key: "gpg.format", Type: TypeString, Values: []string{"openpgp", "x509", "ssh"}},
+	KeySchema{Key: "gpg.openpgp.program", Type: TypeString},
+	KeySchema{Key: "gpg.x509.program", Type: TypeString},
+	KeySchema{Key: "gpg.ssh.program", Type: TypeString},
+	KeySchema{Key: "gpg.ssh.defaultKeyCommand", Type: TypeString},
+	KeySchema{Key: "gpg.ssh.allowedSignersFile", Type: TypePath},
+	KeySchema{Key: "gpg.ssh.revocationFile", Type: TypePath},
+
+	KeySchema{Key: "include.path", Type: TypePath},
+	KeySchema{Key: "includeIf.*.path", Type: TypePath},
+	KeySchema{Key: "url.*.insteadOf", Type: TypeString},
+	KeySchema{Key: "url.*.pushInsteadOf", Type: TypeString},
+	KeySchema{Key: "remote.*.url", Type: TypeString},
+	KeySchema{Key: "remote.*.pushurl", Type: TypeString},
+	KeySchema{Key: "remote.*.fetch", Type: TypeString},
+	KeySchema{Key: "remote.*.push", Type: TypeString},
+	KeySchema{Key: "remote.*.mirror", Type: TypeBool},
+	KeySchema{Key: "remote.*.prune", Type: TypeBool},
+	KeySchema{Key: "remote.*.tagOpt", Type: TypeString, Values: []string{"--tags", "--no-tags"}},
+	KeySchema{Key: "branch.*.remote", Type: TypeString},
+	KeySchema{Key: "branch.*.pushRemote", Type: TypeString},
+	KeySchema{Key: "branch.*.merge", Type: TypeString},
+	KeySchema{Key: "branch.*.rebase", Type: TypeBool, Values: rebaseModes},
+	KeySchema{Key: "branch.autoSetupMerge", Type: TypeBool, Values: []string{"always", "inherit", "simple"}},
+	KeySchema{Key: "branch.autoSetupRebase", Type: TypeString, Values: []string{"never", "local", "remote", "always"}},
+
+	KeySchema{Key: "init.defaultBranch", Type: TypeString},
+	KeySchema{Key: "init.templateDir", Type: TypePath},
+	KeySchema{Key: "protocol.version", Type: TypeInt},
+	KeySchema{Key: "fetch.writeCommitGraph", Type: TypeBool},
+	KeySchema{Key: "fetch.recurseSubmodules", Type: TypeBool, Values: []string{"on-demand"}},
+	KeySchema{Key: "fetch.parallel", Type: TypeInt},
+	KeySchema{Key: "submodule.recurse", Type: TypeBool},
+	KeySchema{Key: "rerere.enabled", Type: TypeBool},
+	KeySchema{Key: "log.showSignature", Type: TypeBool},
+	KeySchema{Key: "tag.sort", Type: TypeString},
+	KeySchema{Key: "diff.algorithm", Type: TypeString, Values: []string{"default", "myers", "minimal", "patience", "histogram"}},
+	KeySchema{Key: "diff.colorMoved", Type: TypeBool, Values: []string{"no", "default", "plain", "blocks", "zebra", "dimmed-zebra"}},
+	KeySchema{Key: "pager.*", Type: TypeString},
+	KeySchema{Key: "fetch.prune", Type: TypeBool},
+	KeySchema{Key: "pull.rebase", Type: TypeBool, Values: rebaseModes},
+	KeySchema{Key: "pull.ff", Type: TypeBool, Values: []string{"only"}},
+	KeySchema{Key: "push.default", Type: TypeString, Values: []string{"nothing", "current", "upstream", "tracking", "simple", "matching"}},
+	KeySchema{Key: "push.autoSetupRemote", Type: TypeBool},
+	KeySchema{Key: "push.followTags", Type: TypeBool},
+	KeySchema{Key: "push.gpgSign", Type: TypeBool, Values: []string{"if-asked"}},
+	KeySchema{Key: "merge.ff", Type: TypeBool, Values: []string{"only"}},
+	KeySchema{Key: "merge.conflictStyle", Type: TypeString, Values: []string{"merge", "diff3", "zdiff3"}},
+	KeySchema{Key: "merge.tool", Type: TypeString},
+	KeySchema{Key: "mergetool.*.cmd", Type: TypeString},
+	KeySchema{Key: "diff.tool", Type: TypeString},
+	KeySchema{Key: "difftool.*.cmd", Type: TypeString},
+	KeySchema{Key: "rebase.autoStash", Type: TypeBool},
+	KeySchema{Key: "rebase.autoSquash", Type: TypeBool},
+	KeySchema{Key: "status.showUntrackedFiles", Type: TypeString, Values: []string{"no", "normal", "all"}},
+	KeySchema{Key: "gc.auto", Type: TypeInt},
+	KeySchema{Key: "gc.reflogExpire", Type: TypeExpiryDate},
+	KeySchema{Key: "gc.pruneExpire", Type: TypeExpiryDate},
+	KeySchema{Key: "help.autoCorrect", Type: TypeInt, Values: []string{"never", "immediate", "prompt"}},
+	KeySchema{Key: "credential.helper", Type: TypeString},
+	KeySchema{Key: "credential.*.helper", Type: TypeString},
+	KeySchema{Key: "credential.*.username", Type: TypeString},
+	KeySchema{Key: "http.proxy", Type: TypeString},
+	KeySchema{Key: "http.*.proxy", Type: TypeString},
+	KeySchema{Key: "http.sslVerify", Type: TypeBool},
+	KeySchema{Key: "http.*.sslVerify", Type: TypeBool},
+	KeySchema{Key: "http.sslCAInfo", Type: TypePath},
+	KeySchema{Key: "http.*.sslCAInfo", Type: TypePath},
+	KeySchema{Key: "http.sslCert", Type: TypePath},
+	KeySchema{Key: "http.*.sslCert", Type: TypePath},
+	KeySchema{Key: "http.sslKey", Type: TypePath},
+	KeySchema{Key: "http.*.sslKey", Type: TypePath},
+	KeySchema{Key: "http.cookieFile", Type: TypePath},
+	KeySchema{Key: "http.*.cookieFile", Type: TypePath},
+	KeySchema{Key: "http.extraHeader", Type: TypeString},
+	KeySchema{Key: "http.*.extraHeader", Type: TypeString},
+	KeySchema{Key: "http.postBuffer", Type: TypeInt},
+	KeySchema{Key: "http.*.postBuffer", Type: TypeInt},
+	KeySchema{Key: "http.version", Type: TypeString, Values: []string{"HTTP/1.1", "HTTP/2"}},
+	KeySchema{Key: "http.*.version", Type: TypeString, Values: []string{"HTTP/1.1", "HTTP/2"}},
+	KeySchema{Key: "safe.directory", Type: TypeString},
+	KeySchema{Key: "filter.*.clean", Type: TypeString},
+	KeySchema{Key: "filter.*.smudge", Type: TypeString},
+	KeySchema{Key: "filter.*.process", Type: TypeString},
+	KeySchema{Key: "filter.*.required", Type: TypeBool},
+	KeySchema{Key: "alias.*", Type: TypeString},
+	KeySchema{Key: "advice.*", Type: TypeBool},
+	KeySchema{Key: "color.*", Type: TypeBool, Values: colorWhen},
+	KeySchema{Key: "color.*.*", Type: TypeColor},
+}
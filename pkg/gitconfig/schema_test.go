@@ -0,0 +1,134 @@
+package gitconfig
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   string
+		opts     ValidateOptions
+		wantErrs []error
+		wantKeys []string
+	}{
+		{
+			name: "valid",
+			config: "[user]\n\tname = John Doe\n\tEmail = john@example.com\n\tsigningKey = ABCDEF\n" +
+				"[commit]\n\tgpgsign\n[gpg]\n\tformat = ssh\n[gpg \"ssh\"]\n\tallowedSignersFile = ~/.ssh/allowed\n" +
+				"[url \"git@github.com:\"]\n\tinsteadOf = https://github.com/\n[core]\n\tautocrlf = input\n\tabbrev = 12\n" +
+				"[color]\n\tui = auto\n\tdiff = false\n[color \"diff\"]\n\tmeta = bold blue\n[alias]\n\tst = status\n" +
+				"[gc]\n\tpruneExpire = 2024-01-31T10:00:00+02:00\n" +
+				"[http \"https://example.com\"]\n\tsslVerify = false\n\tproxy = http://proxy:8080\n" +
+				"[init]\n\tdefaultBranch = main\n[core]\n\tfsmonitor = true\n",
+		},
+		{
+			name:   "unknown keys are ignored by default",
+			config: "[user]\n\tnmae = a\n[foo]\n\tbar = baz\n",
+		},
+		{
+			name:     "unknown keys are reported once",
+			config:   "[user]\n\tnmae = a\n\tnmae = b\n[foo]\n\tbar = baz\n[url \"x\"]\n\tinsteadof = y\n[url]\n\tinsteadof = z\n",
+			opts:     ValidateOptions{UnknownKeys: true},
+			wantErrs: []error{ErrUnknownKey, ErrUnknownKey, ErrUnknownKey},
+			wantKeys: []string{"user.nmae", "foo.bar", "url.insteadof"},
+		},
+		{
+			name:     "wrong types in url specific keys",
+			config:   "[http \"https://example.com\"]\n\tsslVerify = sometimes\n\tpostBuffer = lots\n",
+			wantErrs: []error{ErrInvalidBool, ErrInvalidNumber},
+			wantKeys: []string{"http.https://example.com.sslVerify", "http.https://example.com.postBuffer"},
+		},
+		{
+			name:     "wrong types",
+			config:   "[commit]\n\tgpgSign = maybe\n[core]\n\tcompression = high\n[color \"diff\"]\n\tmeta = bold blu\n[gc]\n\tpruneExpire = someday\n",
+			wantErrs: []error{ErrInvalidBool, ErrInvalidNumber, ErrInvalidColor, ErrInvalidDate},
+			wantKeys: []string{"commit.gpgSign", "core.compression", "color.diff.meta", "gc.pruneExpire"},
+		},
+		{
+			name:     "invalid enum values",
+			config:   "[gpg]\n\tformat = pgp\n\tformat = OpenPGP\n[core]\n\tautocrlf = output\n\tabbrev = yes\n",
+			wantErrs: []error{ErrInvalidEnum, ErrInvalidEnum, ErrInvalidBool, ErrInvalidNumber},
+			wantKeys: []string{"gpg.format", "gpg.format", "core.autocrlf", "core.abbrev"},
+		},
+		{
+			name:     "missing values",
+			config:   "[user]\n\tname\n[gpg]\n\tformat\n[core]\n\tbare\n",
+			wantErrs: []error{ErrEmptyValue, ErrEmptyValue},
+			wantKeys: []string{"user.name", "gpg.format"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gc, err := Parse([]byte(tt.config))
+			if err != nil {
+				t.Fatalf("Parse() error = %v, want %v", err, nil)
+			}
+			err = ValidateWith(gc, tt.opts)
+			if len(tt.wantErrs) == 0 {
+				if err != nil {
+					t.Errorf("ValidateWith() error = %v, want %v", err, nil)
+				}
+				return
+			}
+
+			var errs ValidationErrors
+			if !errors.As(err, &errs) {
+				t.Fatalf("ValidateWith() error = %v, want %T", err, errs)
+			}
+			if len(errs) != len(tt.wantErrs) {
+				t.Fatalf("ValidateWith() returned %d errors, want %d: %v", len(errs), len(tt.wantErrs), err)
+			}
+			for i := range errs {
+				if !errors.Is(errs[i], tt.wantErrs[i]) || errs[i].Key != tt.wantKeys[i] {
+					t.Errorf("ValidateWith() error = %v, want %v for %s", errs[i], tt.wantErrs[i], tt.wantKeys[i])
+				}
+			}
+		})
+	}
+}
+
+func TestSchema_Register(t *testing.T) {
+	s := NewSchema(
+		KeySchema{Key: "git-sw.*", Type: TypeString},
+		KeySchema{Key: "git-sw.enabled", Type: TypeBool},
+	)
+
+	tests := []struct {
+		key    string
+		want   KeyType
+		wantOK bool
+	}{
+		{key: "git-sw.profile", want: TypeString, wantOK: true},
+		{key: "GIT-SW.Enabled", want: TypeBool, wantOK: true},
+		{key: "git-sw.sub.profile", wantOK: false},
+		{key: "user.name", wantOK: false},
+		{key: "invalid", wantOK: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			got, ok := s.Lookup(tt.key)
+			if ok != tt.wantOK || got.Type != tt.want {
+				t.Errorf("Schema.Lookup() = (%v, %v), want (%v, %v)", got.Type, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+
+	gc := New()
+	_ = gc.Set("git-sw.enabled", "sometimes")
+	if err := s.Validate(gc); !errors.Is(err, ErrInvalidBool) {
+		t.Errorf("Schema.Validate() error = %v, want %v", err, ErrInvalidBool)
+	}
+}
+
+func TestDefaultSchema(t *testing.T) {
+	DefaultSchema().Register(KeySchema{Key: "user.name", Type: TypeBool})
+
+	gc := New()
+	_ = gc.Set("user.name", "John Doe")
+	if err := Validate(gc); err != nil {
+		t.Errorf("Validate() error = %v, want %v", err, nil)
+	}
+}
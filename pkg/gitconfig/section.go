@@ -0,0 +1,189 @@
+package gitconfig
+
+import "strings"
+
+// Sections returns every section of the config, spelled the way it first
+// appears, in the order they first appear. Sections without any variable
+// are included.
+func (g GitConfig) Sections() []Section {
+	sections := make([]Section, 0)
+	seen := make(map[Section]struct{})
+	for e := g.lines.front(); e != nil; e = e.next {
+		if e.val.typ != section && e.val.typ != variable {
+			continue
+		}
+		sec := e.val.section.canonical()
+		if _, ok := seen[sec]; ok {
+			continue
+		}
+		seen[sec] = struct{}{}
+		sections = append(sections, e.val.section)
+	}
+
+	return sections
+}
+
+// Subsections returns the subsections of the section with the given name,
+// e.g. the names of the remotes for "remote".
+func (g GitConfig) Subsections(name string) []string {
+	subsections := make([]string, 0)
+	for _, sec := range g.Sections() {
+		if strings.EqualFold(sec.Name, name) && len(sec.Subsection) > 0 {
+			subsections = append(subsections, sec.Subsection)
+		}
+	}
+
+	return subsections
+}
+
+// RemoveSection removes every block of the given section, like
+// "git config --remove-section". A block is a section header with
+// the lines following it, up to the next section header.
+func (g *GitConfig) RemoveSection(name string) error {
+	sec, err := NewSection(name)
+	if err != nil {
+		return err
+	}
+
+	headers := g.headers(sec)
+	if len(headers) == 0 {
+		return ErrSectionNotFound
+	}
+	for _, header := range headers {
+		end := blockEnd(header).next
+		for e := header; e != end; {
+			next := e.next
+			g.lines.remove(e)
+			e = next
+		}
+	}
+	g.data.remove(sec.canonical())
+
+	return nil
+}
+
+// RenameSection renames the section oldName to newName, like
+// "git config --rename-section". Every header of the section is rewritten,
+// anything following a header on the same line is kept.
+func (g *GitConfig) RenameSection(oldName, newName string) error {
+	from, err := NewSection(oldName)
+	if err != nil {
+		return err
+	}
+	to, err := NewSection(newName)
+	if err != nil {
+		return err
+	}
+
+	headers := g.headers(from)
+	if len(headers) == 0 {
+		return ErrSectionNotFound
+	}
+	for _, header := range headers {
+		header.val.raw = renameHeader(header.val.raw, to)
+		for e := header; e != nil && (e == header || e.val.typ != section); e = e.next {
+			e.val.section = to
+		}
+	}
+	g.reindex()
+
+	return nil
+}
+
+// CopySection copies the section oldName to newName, like "git config --copy-section".
+// A copy of every block of the section is inserted right after the block.
+func (g *GitConfig) CopySection(oldName, newName string) error {
+	from, err := NewSection(oldName)
+	if err != nil {
+		return err
+	}
+	to, err := NewSection(newName)
+	if err != nil {
+		return err
+	}
+
+	headers := g.headers(from)
+	if len(headers) == 0 {
+		return ErrSectionNotFound
+	}
+	for _, header := range headers {
+		end := blockEnd(header)
+		at := g.insertLine(end, syntaxNode{
+			typ:     section,
+			raw:     renameHeader(header.val.raw, to),
+			section: to,
+		})
+		for e := header; e != end; {
+			e = e.next
+			line := syntaxNode{typ: e.val.typ, raw: e.val.raw}
+			if line.typ == variable {
+				line.section, line.name, line.value = to, e.val.name, e.val.value
+			}
+			at = g.insertLine(at, line)
+		}
+	}
+	g.reindex()
+
+	return nil
+}
+
+// headers returns the header line of every block of sec.
+func (g GitConfig) headers(sec Section) []*node[syntaxNode] {
+	var headers []*node[syntaxNode]
+	sec = sec.canonical()
+	for e := g.lines.front(); e != nil; e = e.next {
+		if e.val.typ == section && e.val.section.canonical() == sec {
+			headers = append(headers, e)
+		}
+	}
+	return headers
+}
+
+// blockEnd returns the last line of the block starting at header.
+func blockEnd(header *node[syntaxNode]) *node[syntaxNode] {
+	end := header
+	for end.next != nil && end.next.val.typ != section {
+		end = end.next
+	}
+	return end
+}
+
+// reindex rebuilds the index of the variables from the lines.
+func (g *GitConfig) reindex() {
+	g.data = newOrderedMap[Section, *orderedMap[VariableName, []*node[syntaxNode]]]()
+	for e := g.lines.front(); e != nil; e = e.next {
+		if e.val.typ == variable {
+			g.index(e)
+		}
+	}
+}
+
+// renameHeader replaces the section header in raw with the header of sec,
+// keeping the indentation and whatever follows the header.
+func renameHeader(raw string, sec Section) string {
+	indent := indentOf(raw)
+	rest := raw[len(indent):]
+	if end := headerEnd(rest); end > 0 {
+		return indent + sec.String() + rest[end:]
+	}
+
+	return indent + sec.String() + lineEnding(raw)
+}
+
+// headerEnd returns the position right after the ']' closing the section
+// header at the start of s, or -1 if the header isn't closed.
+func headerEnd(s string) int {
+	var quoted bool
+	for i := 1; i < len(s); i++ {
+		switch {
+		case quoted && s[i] == '\\':
+			i++
+		case s[i] == '"':
+			quoted = !quoted
+		case !quoted && s[i] == ']':
+			return i + 1
+		}
+	}
+
+	return -1
+}
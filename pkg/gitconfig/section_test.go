@@ -0,0 +1,222 @@
+package gitconfig
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+const sectionConfig = "# remotes\n" +
+	"[remote \"origin\"] # where I push\n" +
+	"\turl = git@github.com:me/repo.git\n" +
+	"\n" +
+	"[Core]\n" +
+	"\teditor = vim\n" +
+	"[remote \"upstream\"]\n" +
+	"\turl = https://github.com/them/repo\n" +
+	"[empty]\n" +
+	"[remote \"origin\"]\n" +
+	"\tfetch = +refs/heads/*:refs/remotes/origin/*\n"
+
+func TestGitConfig_Sections(t *testing.T) {
+	gc, err := Parse([]byte(sectionConfig))
+	if err != nil {
+		t.Fatalf("Parse() error = %v, want %v", err, nil)
+	}
+
+	want := []Section{{Name: "remote", Subsection: "origin"}, {Name: "Core"}, {Name: "remote", Subsection: "upstream"}, {Name: "empty"}}
+	if got := gc.Sections(); !reflect.DeepEqual(got, want) {
+		t.Errorf("GitConfig.Sections() = %v, want %v", got, want)
+	}
+	if got, want := gc.Subsections("Remote"), []string{"origin", "upstream"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("GitConfig.Subsections() = %v, want %v", got, want)
+	}
+	if got := gc.Subsections("core"); len(got) != 0 {
+		t.Errorf("GitConfig.Subsections() = %v, want %v", got, []string{})
+	}
+}
+
+func TestGitConfig_SectionOperations(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(g *GitConfig) error
+		want    string
+		key     string
+		wantVal []Value
+		wantErr error
+	}{
+		{
+			name: "Remove Section",
+			modify: func(g *GitConfig) error {
+				return g.RemoveSection("remote.origin")
+			},
+			want: "# remotes\n" +
+				"[Core]\n" +
+				"\teditor = vim\n" +
+				"[remote \"upstream\"]\n" +
+				"\turl = https://github.com/them/repo\n" +
+				"[empty]\n",
+			key:     "remote.origin.url",
+			wantErr: ErrKeyNotFound,
+		},
+		{
+			name: "Remove Empty Section",
+			modify: func(g *GitConfig) error {
+				return g.RemoveSection("EMPTY")
+			},
+			want: "# remotes\n" +
+				"[remote \"origin\"] # where I push\n" +
+				"\turl = git@github.com:me/repo.git\n" +
+				"\n" +
+				"[Core]\n" +
+				"\teditor = vim\n" +
+				"[remote \"upstream\"]\n" +
+				"\turl = https://github.com/them/repo\n" +
+				"[remote \"origin\"]\n" +
+				"\tfetch = +refs/heads/*:refs/remotes/origin/*\n",
+		},
+		{
+			name: "Rename Section",
+			modify: func(g *GitConfig) error {
+				return g.RenameSection("remote.origin", "remote.fork")
+			},
+			want: "# remotes\n" +
+				"[remote \"fork\"] # where I push\n" +
+				"\turl = git@github.com:me/repo.git\n" +
+				"\n" +
+				"[Core]\n" +
+				"\teditor = vim\n" +
+				"[remote \"upstream\"]\n" +
+				"\turl = https://github.com/them/repo\n" +
+				"[empty]\n" +
+				"[remote \"fork\"]\n" +
+				"\tfetch = +refs/heads/*:refs/remotes/origin/*\n",
+			key:     "remote.fork.fetch",
+			wantVal: []Value{{"+refs/heads/*:refs/remotes/origin/*"}},
+		},
+		{
+			name: "Rename Into Existing Section",
+			modify: func(g *GitConfig) error {
+				return g.RenameSection("remote.upstream", "core")
+			},
+			want: "# remotes\n" +
+				"[remote \"origin\"] # where I push\n" +
+				"\turl = git@github.com:me/repo.git\n" +
+				"\n" +
+				"[Core]\n" +
+				"\teditor = vim\n" +
+				"[core]\n" +
+				"\turl = https://github.com/them/repo\n" +
+				"[empty]\n" +
+				"[remote \"origin\"]\n" +
+				"\tfetch = +refs/heads/*:refs/remotes/origin/*\n",
+			key:     "core.url",
+			wantVal: []Value{{"https://github.com/them/repo"}},
+		},
+		{
+			name: "Copy Section",
+			modify: func(g *GitConfig) error {
+				return g.CopySection("core", "backup")
+			},
+			want: "# remotes\n" +
+				"[remote \"origin\"] # where I push\n" +
+				"\turl = git@github.com:me/repo.git\n" +
+				"\n" +
+				"[Core]\n" +
+				"\teditor = vim\n" +
+				"[backup]\n" +
+				"\teditor = vim\n" +
+				"[remote \"upstream\"]\n" +
+				"\turl = https://github.com/them/repo\n" +
+				"[empty]\n" +
+				"[remote \"origin\"]\n" +
+				"\tfetch = +refs/heads/*:refs/remotes/origin/*\n",
+			key:     "backup.editor",
+			wantVal: []Value{{"vim"}},
+		},
+		{
+			name: "Copy Section With Multiple Blocks",
+			modify: func(g *GitConfig) error {
+				return g.CopySection("remote.origin", "remote.mirror")
+			},
+			want: "# remotes\n" +
+				"[remote \"origin\"] # where I push\n" +
+				"\turl = git@github.com:me/repo.git\n" +
+				"\n" +
+				"[remote \"mirror\"] # where I push\n" +
+				"\turl = git@github.com:me/repo.git\n" +
+				"\n" +
+				"[Core]\n" +
+				"\teditor = vim\n" +
+				"[remote \"upstream\"]\n" +
+				"\turl = https://github.com/them/repo\n" +
+				"[empty]\n" +
+				"[remote \"origin\"]\n" +
+				"\tfetch = +refs/heads/*:refs/remotes/origin/*\n" +
+				"[remote \"mirror\"]\n" +
+				"\tfetch = +refs/heads/*:refs/remotes/origin/*\n",
+			key:     "remote.mirror.url",
+			wantVal: []Value{{"git@github.com:me/repo.git"}},
+		},
+		{
+			name: "Unset Keeps Empty Section",
+			modify: func(g *GitConfig) error {
+				return g.Unset("remote.upstream.url")
+			},
+			want: "# remotes\n" +
+				"[remote \"origin\"] # where I push\n" +
+				"\turl = git@github.com:me/repo.git\n" +
+				"\n" +
+				"[Core]\n" +
+				"\teditor = vim\n" +
+				"[remote \"upstream\"]\n" +
+				"[empty]\n" +
+				"[remote \"origin\"]\n" +
+				"\tfetch = +refs/heads/*:refs/remotes/origin/*\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gc, err := Parse([]byte(sectionConfig))
+			if err != nil {
+				t.Fatalf("Parse() error = %v, want %v", err, nil)
+			}
+			err = tt.modify(gc)
+			if err != nil {
+				t.Fatalf("modify() error = %v, want %v", err, nil)
+			}
+
+			got, _ := gc.MarshalText()
+			if string(got) != tt.want {
+				t.Errorf("GitConfig.MarshalText() = %q, want %q", got, tt.want)
+			}
+			if len(tt.key) == 0 {
+				return
+			}
+			vals, err := gc.GetAll(tt.key)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("GitConfig.GetAll() error = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(vals, tt.wantVal) {
+				t.Errorf("GitConfig.GetAll() = %v, want %v", vals, tt.wantVal)
+			}
+		})
+	}
+}
+
+func TestGitConfig_SectionNotFound(t *testing.T) {
+	gc, err := Parse([]byte(sectionConfig))
+	if err != nil {
+		t.Fatalf("Parse() error = %v, want %v", err, nil)
+	}
+
+	if err := gc.RemoveSection("remote.gone"); !errors.Is(err, ErrSectionNotFound) {
+		t.Errorf("GitConfig.RemoveSection() error = %v, want %v", err, ErrSectionNotFound)
+	}
+	if err := gc.RenameSection("gone", "here"); !errors.Is(err, ErrSectionNotFound) {
+		t.Errorf("GitConfig.RenameSection() error = %v, want %v", err, ErrSectionNotFound)
+	}
+	if err := gc.CopySection("core", "in*valid"); !errors.Is(err, ErrInvalidSection) {
+		t.Errorf("GitConfig.CopySection() error = %v, want %v", err, ErrInvalidSection)
+	}
+}
@@ -0,0 +1,73 @@
+package gitconfig
+
+import (
+	"fmt"
+	"strings"
+)
+
+// syntaxNode is a single logical line of a config file. raw holds the exact
+// text the line was read from, including line continuations and the line
+// terminator, so an untouched file can be written back byte-for-byte.
+type syntaxNode struct {
+	typ        lineType
+	raw        string
+	section    Section      // section the line belongs to (section and variable lines)
+	name       VariableName // variable lines only
+	value      Value        // variable lines only
+	file       string       // file the line was read from, if known
+	lineNumber int          // line number in file, 0 for lines that weren't parsed
+}
+
+func newVariableLine(sec Section, name VariableName, val Value, indent string) syntaxNode {
+	n := syntaxNode{
+		typ:     variable,
+		raw:     indent,
+		section: sec,
+		name:    name,
+	}
+	n.setValue(val)
+	return n
+}
+
+func newSectionLine(sec Section) syntaxNode {
+	return syntaxNode{
+		typ:     section,
+		raw:     sec.String() + "\n",
+		section: sec,
+	}
+}
+
+// setValue replaces the value of a variable line, quoting and escaping it
+// like git does. The indentation, the spelling of the variable name, the
+// comment following the value and the line terminator are kept as is. As git
+// doesn't allow a comment after a variable without a value, the comment is
+// dropped if val has no value.
+func (n *syntaxNode) setValue(val Value) {
+	n.value = val
+	if !val.HasValue() {
+		n.raw = fmt.Sprintf("%s%s%s", indentOf(n.raw), n.name, lineEnding(n.raw))
+		return
+	}
+	comment := trailingComment(*n)
+	if len(comment) > 0 {
+		comment = " " + comment
+	}
+	n.raw = fmt.Sprintf("%s%s = %s%s%s", indentOf(n.raw), n.name, val.encode(), comment, lineEnding(n.raw))
+}
+
+// indentOf returns the leading whitespace of a line.
+func indentOf(raw string) string {
+	return raw[:len(raw)-len(strings.TrimLeft(raw, " \t"))]
+}
+
+// lineEnding returns the line terminator used by raw. Lines without
+// content (newly created ones) are terminated by "\n".
+func lineEnding(raw string) string {
+	switch {
+	case strings.HasSuffix(raw, "\r\n"):
+		return "\r\n"
+	case strings.HasSuffix(raw, "\n"), strings.TrimSpace(raw) == "":
+		return "\n"
+	}
+	return ""
+}
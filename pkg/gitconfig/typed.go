@@ -0,0 +1,197 @@
+package gitconfig
+
+import (
+	"math"
+	"os/exec"
+	"os/user"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Bool interprets the value as a boolean the way git does: "true", "yes", "on"
+// and any non-zero number are true, "false", "no", "off", "0" and an empty
+// string are false. A variable without a value (e.g. "[core] bare") is true.
+func (val Value) Bool() (bool, error) {
+	switch v := val.v.(type) {
+	case nil:
+		return true, nil
+	case bool:
+		return v, nil
+	case string:
+		return parseBool(val.String())
+	}
+
+	n, err := val.Int()
+	if err != nil {
+		return false, ErrInvalidBool
+	}
+	return n != 0, nil
+}
+
+func parseBool(s string) (bool, error) {
+	switch strings.ToLower(s) {
+	case "true", "yes", "on":
+		return true, nil
+	case "false", "no", "off", "":
+		return false, nil
+	}
+
+	n, err := parseInt(s)
+	if err != nil {
+		return false, ErrInvalidBool
+	}
+	return n != 0, nil
+}
+
+// Int interprets the value as an integer the way git does. The number may be
+// written in decimal, hexadecimal ("0x") or octal (leading "0") and can have
+// a "k", "m" or "g" suffix to multiply it by 1024, 1024² or 1024³.
+func (val Value) Int() (int64, error) {
+	t := reflect.ValueOf(val.v)
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return t.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if t.Uint() > math.MaxInt64 {
+			return 0, ErrInvalidNumber
+		}
+		return int64(t.Uint()), nil
+	case reflect.String:
+		return parseInt(val.String())
+	}
+	return 0, ErrInvalidNumber
+}
+
+func parseInt(s string) (int64, error) {
+	s = strings.TrimLeft(s, " \t\n\v\f\r")
+
+	var factor int64 = 1
+	if len(s) > 0 {
+		switch s[len(s)-1] {
+		case 'k', 'K':
+			factor = 1 << 10
+		case 'm', 'M':
+			factor = 1 << 20
+		case 'g', 'G':
+			factor = 1 << 30
+		}
+		if factor > 1 {
+			s = s[:len(s)-1]
+		}
+	}
+
+	var sign string
+	if len(s) > 0 && (s[0] == '-' || s[0] == '+') {
+		sign, s = s[:1], s[1:]
+	}
+	base := 10
+	switch {
+	case len(s) > 2 && s[0] == '0' && (s[1] == 'x' || s[1] == 'X'):
+		base, s = 16, s[2:]
+	case len(s) > 1 && s[0] == '0':
+		base, s = 8, s[1:]
+	}
+	if len(s) == 0 || s[0] == '+' || s[0] == '-' || strings.ContainsRune(s, '_') {
+		return 0, ErrInvalidNumber
+	}
+
+	n, err := strconv.ParseInt(sign+s, base, 64)
+	if err != nil {
+		return 0, ErrInvalidNumber
+	}
+	if n > math.MaxInt64/factor || n < math.MinInt64/factor {
+		return 0, ErrInvalidNumber
+	}
+	return n * factor, nil
+}
+
+// Path interprets the value as a path. Like git, a leading "~/" is expanded to
+// the user's home directory, "~user/" to the home directory of user, and
+// "%(prefix)/" to the directory git is installed in.
+func (val Value) Path() (string, error) {
+	if val.v == nil {
+		return "", ErrEmptyValue
+	}
+	path := val.String()
+
+	if rest, ok := strings.CutPrefix(path, "%(prefix)/"); ok {
+		return filepath.Join(gitPrefix(), rest), nil
+	}
+	if !strings.HasPrefix(path, "~") {
+		return path, nil
+	}
+
+	name, rest, _ := strings.Cut(path[1:], "/")
+	if len(name) == 0 {
+		return expandHome(path)
+	}
+	u, err := user.Lookup(name)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(u.HomeDir, rest), nil
+}
+
+// gitPrefix returns the directory git is installed in, which is the parent
+// of the directory containing the git executable.
+var gitPrefix = sync.OnceValue(func() string {
+	path, err := exec.LookPath("git")
+	if err != nil {
+		return "/usr"
+	}
+	if real, err := filepath.EvalSymlinks(path); err == nil {
+		path = real
+	}
+	return filepath.Dir(filepath.Dir(path))
+})
+
+// GetBool retrieves the value of a given key as a boolean. See Value.Bool().
+func (g GitConfig) GetBool(key string) (bool, error) {
+	val, err := g.Get(key)
+	if err != nil {
+		return false, err
+	}
+	return val.Bool()
+}
+
+// GetInt retrieves the value of a given key as an integer. See Value.Int().
+func (g GitConfig) GetInt(key string) (int64, error) {
+	val, err := g.Get(key)
+	if err != nil {
+		return 0, err
+	}
+	return val.Int()
+}
+
+// GetPath retrieves the value of a given key as a path. See Value.Path().
+func (g GitConfig) GetPath(key string) (string, error) {
+	val, err := g.Get(key)
+	if err != nil {
+		return "", err
+	}
+	return val.Path()
+}
+
+// GetColor retrieves the value of a given key as an ANSI escape sequence.
+// See Value.Color().
+func (g GitConfig) GetColor(key string) (string, error) {
+	val, err := g.Get(key)
+	if err != nil {
+		return "", err
+	}
+	return val.Color()
+}
+
+// GetExpiryDate retrieves the value of a given key as an expiry date.
+// See Value.ExpiryDate().
+func (g GitConfig) GetExpiryDate(key string) (time.Time, error) {
+	val, err := g.Get(key)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return val.ExpiryDate()
+}
@@ -0,0 +1,212 @@
+package gitconfig
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestValue_Bool(t *testing.T) {
+	tests := []struct {
+		name    string
+		val     Value
+		want    bool
+		wantErr error
+	}{
+		{name: "true", val: Value{"true"}, want: true},
+		{name: "yes", val: Value{"Yes"}, want: true},
+		{name: "on", val: Value{"ON"}, want: true},
+		{name: "one", val: Value{"1"}, want: true},
+		{name: "number", val: Value{"0x10"}, want: true},
+		{name: "quoted", val: Value{`"true"`}, want: true},
+		{name: "false", val: Value{"false"}},
+		{name: "no", val: Value{"no"}},
+		{name: "off", val: Value{"off"}},
+		{name: "zero", val: Value{"0"}},
+		{name: "empty", val: Value{""}},
+		{name: "no value", val: Value{}, want: true},
+		{name: "bool", val: Value{true}, want: true},
+		{name: "int", val: Value{0}},
+		{name: "invalid", val: Value{"maybe"}, wantErr: ErrInvalidBool},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.val.Bool()
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Value.Bool() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Value.Bool() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValue_Int(t *testing.T) {
+	tests := []struct {
+		name    string
+		val     Value
+		want    int64
+		wantErr error
+	}{
+		{name: "decimal", val: Value{"42"}, want: 42},
+		{name: "negative", val: Value{"-42"}, want: -42},
+		{name: "plus sign", val: Value{"+3"}, want: 3},
+		{name: "leading space", val: Value{`" 5"`}, want: 5},
+		{name: "hexadecimal", val: Value{"0x10"}, want: 16},
+		{name: "octal", val: Value{"010"}, want: 8},
+		{name: "kilo", val: Value{"1k"}, want: 1024},
+		{name: "mega", val: Value{"-2m"}, want: -2 << 20},
+		{name: "giga", val: Value{"3G"}, want: 3 << 30},
+		{name: "int", val: Value{7}, want: 7},
+		{name: "trailing space", val: Value{`"5 "`}, wantErr: ErrInvalidNumber},
+		{name: "invalid unit", val: Value{"5t"}, wantErr: ErrInvalidNumber},
+		{name: "underscore", val: Value{"1_0"}, wantErr: ErrInvalidNumber},
+		{name: "empty", val: Value{""}, wantErr: ErrInvalidNumber},
+		{name: "overflow", val: Value{"9223372036854775807k"}, wantErr: ErrInvalidNumber},
+		{name: "bool", val: Value{true}, wantErr: ErrInvalidNumber},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.val.Int()
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Value.Int() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Value.Int() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValue_Path(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	tests := []struct {
+		name string
+		val  Value
+		want string
+	}{
+		{name: "absolute", val: Value{"/etc/gitconfig"}, want: "/etc/gitconfig"},
+		{name: "relative", val: Value{"hooks"}, want: "hooks"},
+		{name: "home", val: Value{"~/.gitignore"}, want: filepath.Join(home, ".gitignore")},
+		{name: "prefix", val: Value{"%(prefix)/share/git-core/templates"}, want: filepath.Join(gitPrefix(), "share/git-core/templates")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.val.Path()
+			if err != nil {
+				t.Fatalf("Value.Path() error = %v, want %v", err, nil)
+			}
+			if got != tt.want {
+				t.Errorf("Value.Path() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValue_Color(t *testing.T) {
+	tests := []struct {
+		name    string
+		val     Value
+		want    string
+		wantErr error
+	}{
+		{name: "empty", val: Value{""}, want: ""},
+		{name: "attributes", val: Value{"bold red ul"}, want: "\033[1;4;31m"},
+		{name: "reset", val: Value{"reset"}, want: "\033[m"},
+		{name: "reset with attribute", val: Value{"reset bold"}, want: "\033[;1m"},
+		{name: "rgb", val: Value{`"#ff0000 black"`}, want: "\033[38;2;255;0;0;40m"},
+		{name: "256 colors", val: Value{"208 normal"}, want: "\033[38;5;208m"},
+		{name: "bright", val: Value{"brightred 12"}, want: "\033[91;104m"},
+		{name: "negated attributes", val: Value{"nobold no-ul"}, want: "\033[22;24m"},
+		{name: "default", val: Value{"default default"}, want: "\033[39;49m"},
+		{name: "too many colors", val: Value{"red green blue"}, wantErr: ErrInvalidColor},
+		{name: "unknown word", val: Value{"shiny"}, wantErr: ErrInvalidColor},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.val.Color()
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Value.Color() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Value.Color() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValue_ExpiryDate(t *testing.T) {
+	current := time.Date(2024, time.March, 15, 12, 0, 0, 0, time.UTC)
+	now = func() time.Time { return current }
+	defer func() { now = time.Now }()
+
+	tests := []struct {
+		name    string
+		val     Value
+		want    time.Time
+		wantErr error
+	}{
+		{name: "never", val: Value{"never"}},
+		{name: "false", val: Value{"false"}},
+		{name: "now", val: Value{"now"}, want: maxTime},
+		{name: "all", val: Value{"all"}, want: maxTime},
+		{name: "dotted relative", val: Value{"2.weeks.ago"}, want: current.AddDate(0, 0, -14)},
+		{name: "spaced relative", val: Value{"3 months ago"}, want: current.AddDate(0, -3, 0)},
+		{name: "combined relative", val: Value{"1.year.6.hours.ago"}, want: current.AddDate(-1, 0, 0).Add(-6 * time.Hour)},
+		{name: "yesterday", val: Value{"yesterday"}, want: current.AddDate(0, 0, -1)},
+		{name: "timestamp", val: Value{"@1700000000"}, want: time.Unix(1700000000, 0)},
+		{name: "date", val: Value{"2024-01-31"}, want: time.Date(2024, time.January, 31, 0, 0, 0, 0, time.UTC)},
+		{name: "rfc 3339", val: Value{"2024-01-31T10:00:00Z"}, want: time.Date(2024, time.January, 31, 10, 0, 0, 0, time.UTC)},
+		{name: "rfc 3339 offset", val: Value{"2024-01-31T10:00:00+02:00"}, want: time.Date(2024, time.January, 31, 8, 0, 0, 0, time.UTC)},
+		{name: "rfc 1123", val: Value{"Mon, 02 Jan 2006 15:04:05 MST"}, want: time.Date(2006, time.January, 2, 15, 4, 5, 0, time.UTC)},
+		{name: "approxidate never", val: Value{`" Never "`}},
+		{name: "approxidate now", val: Value{"NOW"}, want: current},
+		{name: "upper case all", val: Value{"ALL"}, wantErr: ErrInvalidDate},
+		{name: "upper case false", val: Value{"False"}, wantErr: ErrInvalidDate},
+		{name: "invalid", val: Value{"someday"}, wantErr: ErrInvalidDate},
+		{name: "invalid unit", val: Value{"2.fortnights.ago"}, wantErr: ErrInvalidDate},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.val.ExpiryDate()
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Value.ExpiryDate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("Value.ExpiryDate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGitConfig_TypedGetters(t *testing.T) {
+	gc, err := Parse([]byte("[core]\n\tbigFileThreshold = 512m\n\tfilemode = yes\n[color \"diff\"]\n\tnew = green bold\n[gc]\n\treflogExpire = 90.days.ago\n"))
+	if err != nil {
+		t.Fatalf("Parse() error = %v, want %v", err, nil)
+	}
+
+	n, err := gc.GetInt("core.bigfilethreshold")
+	if err != nil || n != 512<<20 {
+		t.Errorf("GitConfig.GetInt() = (%v, %v), want (%v, %v)", n, err, 512<<20, nil)
+	}
+	b, err := gc.GetBool("core.fileMode")
+	if err != nil || !b {
+		t.Errorf("GitConfig.GetBool() = (%v, %v), want (%v, %v)", b, err, true, nil)
+	}
+	color, err := gc.GetColor("color.diff.new")
+	if err != nil || color != "\033[1;32m" {
+		t.Errorf("GitConfig.GetColor() = (%q, %v), want (%q, %v)", color, err, "\033[1;32m", nil)
+	}
+	date, err := gc.GetExpiryDate("gc.reflogExpire")
+	if err != nil || date.IsZero() {
+		t.Errorf("GitConfig.GetExpiryDate() = (%v, %v), want a date", date, err)
+	}
+	_, err = gc.GetPath("core.hooksPath")
+	if !errors.Is(err, ErrKeyNotFound) {
+		t.Errorf("GitConfig.GetPath() error = %v, want %v", err, ErrKeyNotFound)
+	}
+}
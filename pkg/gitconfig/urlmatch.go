@@ -0,0 +1,364 @@
+package gitconfig
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// GetURLMatch retrieves the value of key in section for url, the way git reads
+// variables like "http.<url>.sslVerify". Every "<section>.<pattern>.<key>"
+// whose pattern matches url is a candidate, along with "<section>.<key>":
+//   - the scheme, host and port must be the same, a '*' in the host of the
+//     pattern matches one part of the host name (e.g. "https://*.example.com"),
+//   - the path of the pattern must be the path of url or one of its parent
+//     directories,
+//   - if the pattern has a user name, it must be the user name of url.
+//
+// Like git, the value of the candidate with the longest host is returned, then
+// the longest path, then the one with a user name. "<section>.<key>" is used if
+// no pattern matches. If candidates match equally, the last one wins.
+func (g GitConfig) GetURLMatch(section, key, url string) (Value, error) {
+	m, err := newURLMatcher(section, key, url)
+	if err != nil {
+		return Value{}, err
+	}
+
+	var (
+		val   Value
+		found bool
+	)
+	for e := g.lines.front(); e != nil; e = e.next {
+		if m.match(e.val) {
+			val, found = e.val.value, true
+		}
+	}
+	if !found {
+		return Value{}, ErrKeyNotFound
+	}
+
+	return val, nil
+}
+
+// GetURLMatch retrieves the value of key in section for url along with its origin,
+// going through the configs of the set from the lowest to the highest precedence.
+// See GitConfig.GetURLMatch().
+func (cs ConfigSet) GetURLMatch(section, key, url string) (Entry, error) {
+	m, err := newURLMatcher(section, key, url)
+	if err != nil {
+		return Entry{}, err
+	}
+
+	var (
+		entry Entry
+		found bool
+	)
+	for _, l := range cs.layers {
+		for e := l.config.lines.front(); e != nil; e = e.next {
+			if m.match(e.val) {
+				entry, found = l.entry(e.val), true
+			}
+		}
+	}
+	if !found {
+		return Entry{}, ErrKeyNotFound
+	}
+
+	return entry, nil
+}
+
+// urlMatcher finds the best match for a URL among the variables of a section.
+type urlMatcher struct {
+	url     urlInfo
+	section string
+	name    VariableName
+	best    urlMatch
+}
+
+func newURLMatcher(section, key, url string) (*urlMatcher, error) {
+	sec, err := NewSection(section)
+	if err != nil || sec.hasSubsection() {
+		return nil, ErrInvalidSection
+	}
+	name := VariableName(key)
+	if !name.isValid() {
+		return nil, ErrInvalidVariableName
+	}
+	u, err := normalizeURL(url, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return &urlMatcher{
+		url:     u,
+		section: strings.ToLower(section),
+		name:    name.canonical(),
+	}, nil
+}
+
+// match reports whether line matches at least as well as the previous
+// matching lines. Variables whose pattern isn't a valid URL are ignored.
+func (m *urlMatcher) match(line syntaxNode) bool {
+	if line.typ != variable || strings.ToLower(line.section.Name) != m.section || line.name.canonical() != m.name {
+		return false
+	}
+
+	var got urlMatch
+	if line.section.hasSubsection() {
+		pattern, err := normalizeURL(line.section.Subsection, true)
+		if err != nil {
+			return false
+		}
+		var ok bool
+		got, ok = m.url.match(pattern)
+		if !ok {
+			return false
+		}
+	}
+	if got.compare(m.best) < 0 {
+		return false
+	}
+	m.best = got
+
+	return true
+}
+
+// urlMatch tells how well a pattern matches a URL.
+type urlMatch struct {
+	host int // length of the host of the pattern
+	path int // length of the matching path, including the last '/'
+	user bool
+}
+
+func (a urlMatch) compare(b urlMatch) int {
+	switch {
+	case a.host != b.host:
+		return a.host - b.host
+	case a.path != b.path:
+		return a.path - b.path
+	case a.user != b.user:
+		if a.user {
+			return 1
+		}
+		return -1
+	}
+	return 0
+}
+
+// urlInfo is a URL normalized the way git does before matching URLs: the scheme
+// and the host are in lower case, default ports are removed, the path has no "."
+// and ".." segments and only the characters that need it are percent-encoded.
+type urlInfo struct {
+	scheme  string
+	user    string
+	hasUser bool
+	host    string
+	port    string
+	path    string // the path, query and fragment, always starting with '/'
+}
+
+// match reports whether the pattern p matches u and how well.
+func (u urlInfo) match(p urlInfo) (urlMatch, bool) {
+	if u.scheme != p.scheme {
+		return urlMatch{}, false
+	}
+	if p.hasUser && (!u.hasUser || u.user != p.user) {
+		return urlMatch{}, false
+	}
+	if !matchURLHost(u.host, p.host) || u.port != p.port {
+		return urlMatch{}, false
+	}
+	path := matchURLPath(u.path, p.path)
+	if path == 0 {
+		return urlMatch{}, false
+	}
+
+	return urlMatch{host: len(p.host), path: path, user: p.hasUser}, true
+}
+
+// matchURLHost reports whether host matches pattern, part by part. A part of
+// pattern that is "*" matches any part of host.
+func matchURLHost(host, pattern string) bool {
+	hostParts, patternParts := strings.Split(host, "."), strings.Split(pattern, ".")
+	if len(hostParts) != len(patternParts) {
+		return false
+	}
+	for i := range hostParts {
+		if patternParts[i] != "*" && patternParts[i] != hostParts[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// matchURLPath returns the length of prefix, including a trailing '/', if it is path
+// or one of its parent directories. Otherwise, it returns 0.
+func matchURLPath(path, prefix string) int {
+	if len(prefix) == 0 || prefix == "/" {
+		if len(path) == 0 || path[0] == '/' {
+			return 1
+		}
+		return 0
+	}
+
+	prefix = strings.TrimSuffix(prefix, "/")
+	if !strings.HasPrefix(path, prefix) {
+		return 0
+	}
+	if len(path) == len(prefix) || path[len(prefix)] == '/' {
+		return len(prefix) + 1
+	}
+	return 0
+}
+
+const (
+	urlSchemeChars = "+.-"
+	urlHostChars   = ".-_[:]"
+	urlUnsafeChars = " <>\"%{}|\\^`"
+	urlReserved    = ":/?#[]@!$&'()*+,;="
+)
+
+// normalizeURL parses and normalizes s, a URL of the "scheme://[user[:password]@]host[:port][/path]"
+// form. If allowGlobs is true, the host may contain '*'.
+func normalizeURL(s string, allowGlobs bool) (urlInfo, error) {
+	var u urlInfo
+	invalid := func(reason string) (urlInfo, error) {
+		return urlInfo{}, fmt.Errorf("%w: %s: %s", ErrInvalidURL, reason, s)
+	}
+
+	scheme, rest, ok := strings.Cut(s, "://")
+	if !ok || len(scheme) == 0 || !isAlpha(scheme[0]) || strings.ContainsFunc(scheme, func(r rune) bool {
+		return !(isAlnum(r) || strings.ContainsRune(urlSchemeChars, r))
+	}) {
+		return invalid("missing or invalid scheme")
+	}
+	u.scheme = strings.ToLower(scheme)
+
+	if i := strings.IndexAny(rest, "@/?#"); i >= 0 && rest[i] == '@' {
+		user, _, _ := strings.Cut(rest[:i], ":")
+		u.user, ok = normalizeURLEscapes(user, urlReserved)
+		if !ok {
+			return invalid("invalid %XX escape sequence")
+		}
+		u.hasUser = true
+		rest = rest[i+1:]
+	}
+
+	end := strings.IndexAny(rest, "/?#")
+	if end < 0 {
+		end = len(rest)
+	}
+	hostPort, rest := rest[:end], rest[end:]
+	host, port := hostPort, ""
+	if i := strings.LastIndexAny(hostPort, ":]"); i >= 0 && hostPort[i] == ':' {
+		host, port = hostPort[:i], hostPort[i+1:]
+	}
+	if len(host) == 0 && u.scheme != "file" {
+		return invalid("missing host")
+	}
+	hostChars := urlHostChars
+	if allowGlobs {
+		hostChars += "*"
+	}
+	if strings.ContainsFunc(host, func(r rune) bool {
+		return !(isAlnum(r) || strings.ContainsRune(hostChars, r))
+	}) {
+		return invalid("invalid characters in host name")
+	}
+	u.host = strings.ToLower(host)
+
+	// like an empty one, a default port is the same as no port
+	if len(port) > 0 {
+		port = strings.TrimLeft(port, "0")
+		n, err := strconv.ParseUint(port, 10, 16)
+		if err != nil || n == 0 {
+			return invalid("invalid port number")
+		}
+		if !(u.scheme == "http" && port == "80" || u.scheme == "https" && port == "443") {
+			u.port = port
+		}
+	}
+
+	path, err := normalizeURLPath(rest)
+	if err != nil {
+		return invalid(err.Error())
+	}
+	u.path = path
+
+	return u, nil
+}
+
+// normalizeURLPath removes the "." and ".." segments of the path at the start of s
+// and normalizes the percent-encoding of the path, query and fragment.
+func normalizeURLPath(s string) (string, error) {
+	end := strings.IndexAny(s, "?#")
+	if end < 0 {
+		end = len(s)
+	}
+	path, query := s[:end], s[end:]
+
+	segments := []string{""}
+	if len(path) > 0 {
+		parts := strings.Split(path[1:], "/")
+		for i, seg := range parts {
+			last := i == len(parts)-1
+			switch seg {
+			case ".":
+			case "..":
+				if len(segments) == 1 {
+					return "", errors.New("invalid '..' path segment")
+				}
+				segments = segments[:len(segments)-1]
+			default:
+				seg, ok := normalizeURLEscapes(seg, urlReserved)
+				if !ok {
+					return "", errors.New("invalid %XX escape sequence")
+				}
+				segments = append(segments, seg)
+				continue
+			}
+			if last {
+				segments = append(segments, "")
+			}
+		}
+	}
+	if len(segments) == 1 {
+		segments = append(segments, "")
+	}
+
+	query, ok := normalizeURLEscapes(query, urlReserved)
+	if !ok {
+		return "", errors.New("invalid %XX escape sequence")
+	}
+
+	return strings.Join(segments, "/") + query, nil
+}
+
+// normalizeURLEscapes percent-encodes the characters of s that must be and decodes
+// the others, except for those in keep which are left encoded if they were.
+// ok is false if s contains an invalid escape sequence.
+func normalizeURLEscapes(s, keep string) (string, bool) {
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		ch, escaped := s[i], false
+		if ch == '%' {
+			if i+2 >= len(s) {
+				return "", false
+			}
+			n, err := strconv.ParseUint(s[i+1:i+3], 16, 8)
+			if err != nil {
+				return "", false
+			}
+			ch, escaped = byte(n), true
+			i += 2
+		}
+		if ch <= 0x1f || ch >= 0x7f || strings.IndexByte(urlUnsafeChars, ch) >= 0 ||
+			(escaped && strings.IndexByte(keep, ch) >= 0) {
+			fmt.Fprintf(&sb, "%%%02X", ch)
+		} else {
+			sb.WriteByte(ch)
+		}
+	}
+	return sb.String(), true
+}
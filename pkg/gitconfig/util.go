@@ -15,3 +15,14 @@ func isNum[T char](ch T) bool {
 func isAlnum[T char](ch T) bool {
 	return isAlpha(ch) || isNum(ch)
 }
+
+// isSpace reports whether ch is an ASCII whitespace character. Unlike
+// unicode.IsSpace, bytes of multi-byte UTF-8 characters (e.g. 0x85 and 0xA0)
+// aren't whitespace.
+func isSpace[T char](ch T) bool {
+	switch ch {
+	case ' ', '\t', '\n', '\v', '\f', '\r':
+		return true
+	}
+	return false
+}
@@ -0,0 +1,154 @@
+package gitconfig
+
+import "strings"
+
+// Remote is a remote.<name> section.
+type Remote struct {
+	Name     string
+	URLs     []string // remote.<name>.url, as written
+	PushURLs []string // remote.<name>.pushurl, as written
+	Fetch    []string // remote.<name>.fetch refspecs
+	Push     []string // remote.<name>.push refspecs
+}
+
+// Branch is a branch.<name> section.
+type Branch struct {
+	Name       string
+	Remote     string   // branch.<name>.remote, "." for a local upstream
+	PushRemote string   // branch.<name>.pushRemote
+	Merge      []string // branch.<name>.merge, refs of the upstream branch on Remote
+	Rebase     string   // branch.<name>.rebase
+}
+
+// Upstream returns the upstream of b the way "git branch -vv" shows it when
+// the remote uses the default fetch refspec, e.g. "origin/main", or the name
+// of a local branch if Remote is ".". It returns an empty string if b has no
+// upstream.
+func (b Branch) Upstream() string {
+	if len(b.Remote) == 0 || len(b.Merge) == 0 {
+		return ""
+	}
+	name := strings.TrimPrefix(b.Merge[0], "refs/heads/")
+	if b.Remote == "." {
+		return name
+	}
+	return b.Remote + "/" + name
+}
+
+// Include is an include.path or an includeIf.<condition>.path variable.
+type Include struct {
+	Path       string // as written, see Load() for how it is resolved
+	Condition  string // empty for include.path
+	File       string // file the variable was read from, if known
+	LineNumber int    // 0 if the variable wasn't read from a file
+}
+
+// Remotes returns every remote of g, in the order they first appear. Values
+// without a value (e.g. "[remote "origin"] url") are ignored.
+func (g GitConfig) Remotes() []Remote {
+	var remotes []Remote
+	index := make(map[string]int)
+	for e := g.lines.front(); e != nil; e = e.next {
+		line := e.val
+		if !isViewVariable(line, "remote") {
+			continue
+		}
+		name := line.section.Subsection
+		i, ok := index[name]
+		if !ok {
+			i = len(remotes)
+			index[name] = i
+			remotes = append(remotes, Remote{Name: name})
+		}
+
+		r := &remotes[i]
+		switch val := line.value.String(); line.name.canonical() {
+		case "url":
+			r.URLs = append(r.URLs, val)
+		case "pushurl":
+			r.PushURLs = append(r.PushURLs, val)
+		case "fetch":
+			r.Fetch = append(r.Fetch, val)
+		case "push":
+			r.Push = append(r.Push, val)
+		}
+	}
+
+	return remotes
+}
+
+// RemoteURLs returns the URLs git fetches from and pushes to for r, with the
+// url.<base>.insteadOf and url.<base>.pushInsteadOf variables of g applied.
+// See RewriteURL().
+func (g GitConfig) RemoteURLs(r Remote) (fetch, push []string) {
+	var rw urlRewriter
+	for e := g.lines.front(); e != nil; e = e.next {
+		rw.read(e.val)
+	}
+	return rw.rewrite(r.URLs, r.PushURLs)
+}
+
+// Branches returns every branch of g, in the order they first appear. For
+// single-valued variables, the last value wins.
+func (g GitConfig) Branches() []Branch {
+	var branches []Branch
+	index := make(map[string]int)
+	for e := g.lines.front(); e != nil; e = e.next {
+		line := e.val
+		if !isViewVariable(line, "branch") {
+			continue
+		}
+		name := line.section.Subsection
+		i, ok := index[name]
+		if !ok {
+			i = len(branches)
+			index[name] = i
+			branches = append(branches, Branch{Name: name})
+		}
+
+		b := &branches[i]
+		switch val := line.value.String(); line.name.canonical() {
+		case "remote":
+			b.Remote = val
+		case "pushremote":
+			b.PushRemote = val
+		case "merge":
+			b.Merge = append(b.Merge, val)
+		case "rebase":
+			b.Rebase = val
+		}
+	}
+
+	return branches
+}
+
+// Includes returns every include.path and includeIf.<condition>.path of g, in
+// order. Like git, includeIf sections without a condition are ignored.
+func (g GitConfig) Includes() []Include {
+	var includes []Include
+	for e := g.lines.front(); e != nil; e = e.next {
+		line := e.val
+		if line.typ != variable || line.name.canonical() != "path" || !line.value.HasValue() {
+			continue
+		}
+		sec := line.section.canonical()
+		if !(sec.Name == "include" && !sec.hasSubsection() || sec.Name == "includeif" && len(sec.Subsection) > 0) {
+			continue
+		}
+		includes = append(includes, Include{
+			Path:       line.value.String(),
+			Condition:  sec.Subsection,
+			File:       line.file,
+			LineNumber: line.lineNumber,
+		})
+	}
+
+	return includes
+}
+
+// isViewVariable reports whether line is a variable with a value in a
+// subsection of the section name.
+func isViewVariable(line syntaxNode, name string) bool {
+	return line.typ == variable && strings.ToLower(line.section.Name) == name &&
+		len(line.section.Subsection) > 0 && line.value.HasValue()
+}
@@ -0,0 +1,124 @@
+package gitconfig
+
+import (
+	"reflect"
+	"testing"
+)
+
+const viewsConfig = `[remote "origin"]
+	url = https://github.com/me/repo.git
+	fetch = +refs/heads/*:refs/remotes/origin/*
+[remote "upstream"]
+	url = https://example.com/repo
+	url = https://gitlab.com/repo
+	pushurl = ssh://push.example.com/repo
+	mirror
+[Remote "origin"]
+	Fetch = +refs/tags/*:refs/tags/*
+	push = refs/heads/main
+[remote]
+	url = nameless
+[branch "main"]
+	remote = origin
+	merge = refs/heads/main
+	rebase = true
+[branch "feature"]
+	remote = .
+	merge = refs/heads/main
+	pushRemote = upstream
+[branch "main"]
+	remote = upstream
+[branch "local"]
+	rebase = false
+[include]
+	path = ~/.gitconfig-common
+[includeIf "gitdir:~/work/"]
+	path = work.inc
+[includeIf]
+	path = ignored
+[include]
+	path
+[url "git@github.com:"]
+	insteadOf = https://github.com/
+[url "ssh://push.example.com/"]
+	pushInsteadOf = https://example.com/
+`
+
+func TestGitConfig_Remotes(t *testing.T) {
+	gc, err := Parse([]byte(viewsConfig))
+	if err != nil {
+		t.Fatalf("Parse() error = %v, want %v", err, nil)
+	}
+
+	want := []Remote{
+		{
+			Name:  "origin",
+			URLs:  []string{"https://github.com/me/repo.git"},
+			Fetch: []string{"+refs/heads/*:refs/remotes/origin/*", "+refs/tags/*:refs/tags/*"},
+			Push:  []string{"refs/heads/main"},
+		},
+		{
+			Name:     "upstream",
+			URLs:     []string{"https://example.com/repo", "https://gitlab.com/repo"},
+			PushURLs: []string{"ssh://push.example.com/repo"},
+		},
+	}
+	if got := gc.Remotes(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Remotes() = %+v, want %+v", got, want)
+	}
+
+	tests := []struct {
+		remote    Remote
+		wantFetch []string
+		wantPush  []string
+	}{
+		{remote: want[0], wantFetch: []string{"git@github.com:me/repo.git"}, wantPush: []string{"git@github.com:me/repo.git"}},
+		{remote: want[1], wantFetch: want[1].URLs, wantPush: want[1].PushURLs},
+		{remote: Remote{URLs: want[1].URLs}, wantFetch: want[1].URLs, wantPush: []string{"ssh://push.example.com/repo"}},
+	}
+	for _, tt := range tests {
+		fetch, push := gc.RemoteURLs(tt.remote)
+		if !reflect.DeepEqual(fetch, tt.wantFetch) || !reflect.DeepEqual(push, tt.wantPush) {
+			t.Errorf("RemoteURLs() = (%q, %q), want (%q, %q)", fetch, push, tt.wantFetch, tt.wantPush)
+		}
+	}
+}
+
+func TestGitConfig_Branches(t *testing.T) {
+	gc, err := Parse([]byte(viewsConfig))
+	if err != nil {
+		t.Fatalf("Parse() error = %v, want %v", err, nil)
+	}
+
+	want := []Branch{
+		{Name: "main", Remote: "upstream", Merge: []string{"refs/heads/main"}, Rebase: "true"},
+		{Name: "feature", Remote: ".", PushRemote: "upstream", Merge: []string{"refs/heads/main"}},
+		{Name: "local", Rebase: "false"},
+	}
+	got := gc.Branches()
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Branches() = %+v, want %+v", got, want)
+	}
+
+	wantUpstream := []string{"upstream/main", "main", ""}
+	for i := range got {
+		if u := got[i].Upstream(); u != wantUpstream[i] {
+			t.Errorf("Branch.Upstream() = %q, want %q", u, wantUpstream[i])
+		}
+	}
+}
+
+func TestGitConfig_Includes(t *testing.T) {
+	gc, err := Parse([]byte(viewsConfig))
+	if err != nil {
+		t.Fatalf("Parse() error = %v, want %v", err, nil)
+	}
+
+	want := []Include{
+		{Path: "~/.gitconfig-common", LineNumber: 27},
+		{Path: "work.inc", Condition: "gitdir:~/work/", LineNumber: 29},
+	}
+	if got := gc.Includes(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Includes() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,288 @@
+package gitconfig
+
+import "strings"
+
+// wildFlags changes the behaviour of wildmatch.
+type wildFlags int
+
+const (
+	// wmCaseFold makes the match case-insensitive.
+	wmCaseFold wildFlags = 1 << iota
+	// wmPathname prevents '*', '?' and bracket expressions from matching '/'.
+	// "**" still matches across directories when it's a whole path component.
+	wmPathname
+)
+
+const (
+	wmMatch = iota
+	wmNoMatch
+	wmAbortAll
+	wmAbortToStarStar
+)
+
+// wildmatch reports whether text matches the glob pattern, following the
+// semantics of git's wildmatch.
+func wildmatch(pattern, text string, flags wildFlags) bool {
+	return dowild(pattern, text, flags) == wmMatch
+}
+
+// charAt returns s[i], or 0 if i is out of range.
+func charAt(s string, i int) byte {
+	if i < len(s) {
+		return s[i]
+	}
+	return 0
+}
+
+func toLower(ch byte) byte {
+	if ch >= 'A' && ch <= 'Z' {
+		return ch + 'a' - 'A'
+	}
+	return ch
+}
+
+func toUpper(ch byte) byte {
+	if ch >= 'a' && ch <= 'z' {
+		return ch - 'a' + 'A'
+	}
+	return ch
+}
+
+func isGlobSpecial(ch byte) bool {
+	return ch == '*' || ch == '?' || ch == '[' || ch == '\\'
+}
+
+func dowild(p, text string, flags wildFlags) int {
+	var (
+		pi, ti   int
+		caseFold = flags&wmCaseFold != 0
+		pathname = flags&wmPathname != 0
+	)
+
+	for ; pi < len(p); pi, ti = pi+1, ti+1 {
+		pCh, tCh := p[pi], charAt(text, ti)
+		if tCh == 0 && pCh != '*' {
+			return wmAbortAll
+		}
+		if caseFold {
+			tCh, pCh = toLower(tCh), toLower(pCh)
+		}
+
+		switch pCh {
+		case '\\':
+			// literal match with the following character
+			pi++
+			pCh = charAt(p, pi)
+			if caseFold {
+				pCh = toLower(pCh)
+			}
+			if tCh != pCh {
+				return wmNoMatch
+			}
+		default:
+			if tCh != pCh {
+				return wmNoMatch
+			}
+		case '?':
+			if pathname && tCh == '/' {
+				return wmNoMatch
+			}
+		case '*':
+			var matchSlash bool
+			pi++
+			if charAt(p, pi) == '*' {
+				prev := pi - 2
+				for pi++; charAt(p, pi) == '*'; pi++ {
+				}
+				next := charAt(p, pi)
+				if (prev < 0 || p[prev] == '/') &&
+					(next == 0 || next == '/' || (next == '\\' && charAt(p, pi+1) == '/')) {
+					// "foo/**/bar" matches both "foo/bar" and "foo/a/bar"
+					if next == '/' && dowild(p[pi+1:], text[ti:], flags) == wmMatch {
+						return wmMatch
+					}
+					matchSlash = true
+				}
+			} else {
+				// without wmPathname, '*' is the same as "**"
+				matchSlash = !pathname
+			}
+
+			if pi == len(p) {
+				// trailing "**" matches everything, trailing '*' matches
+				// only if there are no more slashes
+				if !matchSlash && strings.IndexByte(text[ti:], '/') > -1 {
+					return wmNoMatch
+				}
+				return wmMatch
+			} else if !matchSlash && p[pi] == '/' {
+				// a single '*' followed by a slash matches the next directory
+				slash := strings.IndexByte(text[ti:], '/')
+				if slash == -1 {
+					return wmNoMatch
+				}
+				// the slash is consumed by the loop
+				ti += slash
+				continue
+			}
+
+			for tCh != 0 {
+				// advance faster when '*' is followed by a literal
+				if !isGlobSpecial(p[pi]) {
+					lit := p[pi]
+					if caseFold {
+						lit = toLower(lit)
+					}
+					for tCh = charAt(text, ti); tCh != 0 && (matchSlash || tCh != '/'); tCh = charAt(text, ti) {
+						if caseFold {
+							tCh = toLower(tCh)
+						}
+						if tCh == lit {
+							break
+						}
+						ti++
+					}
+					if tCh != lit {
+						return wmNoMatch
+					}
+				}
+				matched := dowild(p[pi:], text[ti:], flags)
+				if matched != wmNoMatch {
+					if !matchSlash || matched != wmAbortToStarStar {
+						return matched
+					}
+				} else if !matchSlash && tCh == '/' {
+					return wmAbortToStarStar
+				}
+				ti++
+				tCh = charAt(text, ti)
+			}
+			return wmAbortAll
+		case '[':
+			pi++
+			pCh = charAt(p, pi)
+			if pCh == '^' {
+				pCh = '!'
+			}
+			negated := pCh == '!'
+			if negated {
+				pi++
+				pCh = charAt(p, pi)
+			}
+
+			var (
+				prevCh  byte
+				matched bool
+			)
+			for {
+				switch {
+				case pCh == 0:
+					return wmAbortAll
+				case pCh == '\\':
+					pi++
+					pCh = charAt(p, pi)
+					if pCh == 0 {
+						return wmAbortAll
+					}
+					if tCh == pCh {
+						matched = true
+					}
+				case pCh == '-' && prevCh != 0 && charAt(p, pi+1) != 0 && charAt(p, pi+1) != ']':
+					pi++
+					pCh = charAt(p, pi)
+					if pCh == '\\' {
+						pi++
+						pCh = charAt(p, pi)
+						if pCh == 0 {
+							return wmAbortAll
+						}
+					}
+					if tCh <= pCh && tCh >= prevCh {
+						matched = true
+					} else if caseFold && tCh >= 'a' && tCh <= 'z' {
+						if upper := toUpper(tCh); upper <= pCh && upper >= prevCh {
+							matched = true
+						}
+					}
+					pCh = 0 // a range can't start with the end of the previous one
+				case pCh == '[' && charAt(p, pi+1) == ':':
+					start := pi + 2
+					end := start
+					for charAt(p, end) != 0 && charAt(p, end) != ']' {
+						end++
+					}
+					if charAt(p, end) == 0 {
+						return wmAbortAll
+					}
+					if end-start-1 < 0 || p[end-1] != ':' {
+						// didn't find ":]", treat it like a normal character
+						if tCh == pCh {
+							matched = true
+						}
+						break
+					}
+					ok, valid := matchCharClass(p[start:end-1], tCh, caseFold)
+					if !valid {
+						return wmAbortAll
+					}
+					if ok {
+						matched = true
+					}
+					pi = end
+					pCh = 0
+				default:
+					if tCh == pCh {
+						matched = true
+					}
+				}
+
+				prevCh = pCh
+				pi++
+				pCh = charAt(p, pi)
+				if pCh == ']' {
+					break
+				}
+			}
+			if matched == negated || (pathname && tCh == '/') {
+				return wmNoMatch
+			}
+		}
+	}
+
+	if ti < len(text) {
+		return wmNoMatch
+	}
+	return wmMatch
+}
+
+// matchCharClass reports whether ch belongs to the POSIX character class
+// name (e.g. "alpha" for "[:alpha:]"). valid is false for unknown classes.
+func matchCharClass(name string, ch byte, caseFold bool) (ok, valid bool) {
+	switch name {
+	case "alnum":
+		return isAlnum(ch), true
+	case "alpha":
+		return isAlpha(ch), true
+	case "blank":
+		return ch == ' ' || ch == '\t', true
+	case "cntrl":
+		return ch < 0x20 || ch == 0x7f, true
+	case "digit":
+		return isNum(ch), true
+	case "graph":
+		return ch > 0x20 && ch < 0x7f, true
+	case "lower":
+		return ch >= 'a' && ch <= 'z', true
+	case "print":
+		return ch >= 0x20 && ch < 0x7f, true
+	case "punct":
+		return ch > 0x20 && ch < 0x7f && !isAlnum(ch), true
+	case "space":
+		return ch == ' ' || (ch >= '\t' && ch <= '\r'), true
+	case "upper":
+		return (ch >= 'A' && ch <= 'Z') || (caseFold && ch >= 'a' && ch <= 'z'), true
+	case "xdigit":
+		return isNum(ch) || (ch >= 'a' && ch <= 'f') || (ch >= 'A' && ch <= 'F'), true
+	}
+	return false, false
+}
@@ -0,0 +1,78 @@
+package gitconfig
+
+import "testing"
+
+func TestWildmatch(t *testing.T) {
+	tests := []struct {
+		pattern, text string
+		flags         wildFlags
+		want          bool
+	}{
+		{pattern: "foo", text: "foo", want: true},
+		{pattern: "bar", text: "foo"},
+		{pattern: "", text: "", want: true},
+		{pattern: "???", text: "foo", want: true},
+		{pattern: "??", text: "foo"},
+		{pattern: "*", text: "foo", want: true},
+		{pattern: "f*", text: "foo", want: true},
+		{pattern: "*f", text: "foo"},
+		{pattern: "*foo*", text: "foo", want: true},
+		{pattern: "*ob*a*r*", text: "foobar", want: true},
+		{pattern: "*ab", text: "aaaaaaabababab", want: true},
+		{pattern: `foo\*`, text: "foo*", want: true},
+		{pattern: `foo\*bar`, text: "foobar"},
+		{pattern: `f\\oo`, text: `f\oo`, want: true},
+		{pattern: "*[al]?", text: "ball", want: true},
+		{pattern: "[ten]", text: "ten"},
+		{pattern: "**[!te]", text: "ten", want: true},
+		{pattern: "**[!ten]", text: "ten"},
+		{pattern: "t[a-g]n", text: "ten", want: true},
+		{pattern: "t[!a-g]n", text: "ten"},
+		{pattern: "t[!a-g]n", text: "ton", want: true},
+		{pattern: "t[^a-g]n", text: "ton", want: true},
+		{pattern: "a[]]b", text: "a]b", want: true},
+		{pattern: "a[]-]b", text: "a-b", want: true},
+		{pattern: "a[]-]b", text: "aab"},
+		{pattern: "a[]a-]b", text: "aab", want: true},
+		{pattern: "[[:alpha:]][[:digit:]][[:upper:]]", text: "a1B", want: true},
+		{pattern: "[[:digit:][:upper:][:space:]]", text: "a"},
+		{pattern: "[a-c[:digit:]x-z]", text: "5", want: true},
+		{pattern: "[[:digit:][:upper:][:spaci:]]", text: "1"},
+		{pattern: "-*-*-*-*-*-*-12-*-*-*-m-*-*-*", text: "-adobe-courier-bold-o-normal--12-120-75-75-m-70-iso8859-1", want: true},
+		{pattern: "foo*bar", text: "foo/baz/bar", flags: wmPathname},
+		{pattern: "foo*bar", text: "foo/baz/bar", want: true},
+		{pattern: "foo?bar", text: "foo/bar", flags: wmPathname},
+		{pattern: "foo[/]bar", text: "foo/bar", flags: wmPathname},
+		{pattern: "**/foo", text: "foo", flags: wmPathname, want: true},
+		{pattern: "**/foo", text: "bar/baz/foo", flags: wmPathname, want: true},
+		{pattern: "*/foo", text: "bar/baz/foo", flags: wmPathname},
+		{pattern: "**/bar*", text: "foo/bar/baz", flags: wmPathname},
+		{pattern: "**/bar/*", text: "deep/foo/bar/baz", flags: wmPathname, want: true},
+		{pattern: "**/bar/*", text: "deep/foo/bar/baz/", flags: wmPathname},
+		{pattern: "**/bar/**", text: "deep/foo/bar/baz/", flags: wmPathname, want: true},
+		{pattern: "**/bar/*", text: "deep/foo/bar", flags: wmPathname},
+		{pattern: "**/bar/**", text: "deep/foo/bar/", flags: wmPathname, want: true},
+		{pattern: "*/bar/**", text: "foo/bar/baz/x", flags: wmPathname, want: true},
+		{pattern: "*/bar/**", text: "deep/foo/bar/baz/x", flags: wmPathname},
+		{pattern: "**/bar/*/*", text: "deep/foo/bar/baz/x", flags: wmPathname, want: true},
+		{pattern: "**/*a*b*g*n*t", text: "abcd/abcdefg/abcdefghijk/abcdefghijklmnop.txt", flags: wmPathname, want: true},
+		{pattern: "**/*a*b*g*n*t", text: "abcd/abcdefg/abcdefghijk/abcdefghijklmnop.txtz", flags: wmPathname},
+		{pattern: "*/*/*", text: "foo/bar", flags: wmPathname},
+		{pattern: "*/*/*", text: "foo/bba/arr", flags: wmPathname, want: true},
+		{pattern: "*/*/*", text: "foo/bb/aa/rr", flags: wmPathname},
+		{pattern: "**/**/**", text: "foo/bb/aa/rr", flags: wmPathname, want: true},
+		{pattern: "*/*X*/*/*i", text: "ab/cXd/efXg/hi", flags: wmPathname, want: true},
+		{pattern: "**/*X*/**/*i", text: "ab/cXd/efXg/hi", flags: wmPathname, want: true},
+		{pattern: "[A-Z]", text: "a"},
+		{pattern: "[A-Z]", text: "a", flags: wmCaseFold, want: true},
+		{pattern: "[[:upper:]]", text: "a", flags: wmCaseFold, want: true},
+		{pattern: "FOO/*", text: "foo/bar", flags: wmCaseFold | wmPathname, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.text, func(t *testing.T) {
+			if got := wildmatch(tt.pattern, tt.text, tt.flags); got != tt.want {
+				t.Errorf("wildmatch(%q, %q, %d) = %v, want %v", tt.pattern, tt.text, tt.flags, got, tt.want)
+			}
+		})
+	}
+}
@@ -19,6 +19,15 @@ type Profile struct {
 	IsActive      bool
 }
 
+// profileConfig holds the keys set when a profile is created.
+type profileConfig struct {
+	Name       string    `gitconfig:"user.name"`
+	Email      string    `gitconfig:"user.email"`
+	SigningKey string    `gitconfig:"user.signingKey,omitempty"`
+	GPGFormat  GPGFormat `gitconfig:"gpg.format,omitempty"`
+	GPGSign    bool      `gitconfig:"commit.gpgsign,omitempty"`
+}
+
 func getProfilePath(profileName string) (string, error) {
 	dirName, err := hash(profileName)
 	if err != nil {
@@ -27,7 +36,8 @@ func getProfilePath(profileName string) (string, error) {
 	return filepath.Join(saveDirPath, dirName), nil
 }
 
-func saveProfile(dirPath string, profileName string, config *gitconfig.GitConfig) (err error) {
+// saveProfile creates the directory of a profile, with content as its config.
+func saveProfile(dirPath string, profileName string, content []byte) (err error) {
 	err = os.MkdirAll(dirPath, 0o744)
 	if err != nil {
 		return err
@@ -40,7 +50,7 @@ func saveProfile(dirPath string, profileName string, config *gitconfig.GitConfig
 			}
 		}
 	}()
-	err = config.Save(filepath.Join(dirPath, ".gitconfig"))
+	err = os.WriteFile(filepath.Join(dirPath, ".gitconfig"), content, 0o666)
 	if err != nil {
 		return err
 	}
@@ -61,8 +71,9 @@ func saveProfile(dirPath string, profileName string, config *gitconfig.GitConfig
 	return nil
 }
 
+// copyDefault copies the global config to the default profile, byte for byte,
+// so that it keeps whatever git-sw can't parse.
 func copyDefault() error {
-	var defaultConf *gitconfig.GitConfig
 	dirName, err := getProfilePath(defaultConfigName)
 	if err != nil {
 		return err
@@ -71,35 +82,20 @@ func copyDefault() error {
 	if err != nil {
 		return err
 	}
-	homeDir, err := os.UserHomeDir()
+	configPath, err := gitConfigPath(true)
 	if err != nil {
 		return err
 	}
-	configPath := filepath.Join(homeDir, ".gitconfig")
 
 	configContent, err := os.ReadFile(configPath)
-	if err != nil {
-		if errors.Is(err, os.ErrNotExist) {
-			defaultConf = gitconfig.New()
-			err = defaultConf.Save(configPath)
-			if err != nil {
-				return err
-			}
-			goto saveProfile
-		}
-		return err
-	}
-	defaultConf, err = gitconfig.Parse(configContent)
-	if err != nil {
-		return err
+	if errors.Is(err, os.ErrNotExist) {
+		err = gitconfig.New().Save(configPath)
 	}
-saveProfile:
-	err = saveProfile(dirName, defaultConfigName, defaultConf)
 	if err != nil {
 		return err
 	}
 
-	return nil
+	return saveProfile(dirName, defaultConfigName, configContent)
 }
 
 func getCurrentProfile() (string, error) {
@@ -23,6 +23,7 @@ func validateNotEmpty(s string) error {
 func displayCreateForm() (Profile, error) {
 	var (
 		profile Profile
+		config  profileConfig
 		err     error
 	)
 	profile.Config = gitconfig.New()
@@ -96,19 +97,11 @@ func displayCreateForm() (Profile, error) {
 	if err != nil {
 		return Profile{}, err
 	}
-	name, err := gitNamePrompt.Run()
+	config.Name, err = gitNamePrompt.Run()
 	if err != nil {
 		return Profile{}, err
 	}
-	err = profile.Config.Set("user.name", name)
-	if err != nil {
-		return Profile{}, err
-	}
-	email, err := gitEmailPrompt.Run()
-	if err != nil {
-		return Profile{}, err
-	}
-	err = profile.Config.Set("user.email", email)
+	config.Email, err = gitEmailPrompt.Run()
 	if err != nil {
 		return Profile{}, err
 	}
@@ -118,23 +111,20 @@ func displayCreateForm() (Profile, error) {
 		if err != nil {
 			return Profile{}, err
 		}
-		keyFormat := gpgFormat[ix]
-		signingKey, err := getSigningKeyPrompt(keyFormat).Run()
-		if err != nil {
-			return Profile{}, err
-		}
-		err = profile.Config.Set("gpg.format", string(keyFormat))
-		if err != nil {
-			return Profile{}, err
-		}
-		err = profile.Config.Set("user.signingKey", signingKey)
-		if err != nil {
-			return Profile{}, err
-		}
-		err = profile.Config.Set("commit.gpgsign", "true")
+		config.GPGFormat = gpgFormat[ix]
+		config.SigningKey, err = getSigningKeyPrompt(config.GPGFormat).Run()
 		if err != nil {
 			return Profile{}, err
 		}
+		config.GPGSign = true
+	}
+	err = profile.Config.Encode(config)
+	if err != nil {
+		return Profile{}, err
+	}
+	err = gitconfig.Validate(profile.Config)
+	if err != nil {
+		return Profile{}, err
 	}
 
 	return profile, nil
@@ -197,16 +187,45 @@ func displayProfileList(profiles []Profile) error {
 	return nil
 }
 
+func displayConfigChanges(changes gitconfig.Changes) {
+	if len(changes) == 0 {
+		fmt.Println("No changes.")
+		return
+	}
+	diff := strings.TrimSuffix(changes.Unified("before", "after"), "\n")
+	for _, line := range strings.Split(diff, "\n") {
+		switch {
+		case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"):
+			line = promptui.Styler(promptui.FGBold)(line)
+		case strings.HasPrefix(line, "+"):
+			line = promptui.Styler(promptui.FGGreen)(line)
+		case strings.HasPrefix(line, "-"):
+			line = promptui.Styler(promptui.FGRed)(line)
+		}
+		fmt.Println(line)
+	}
+}
+
+// displayConfigWarnings prints the variables that don't match the schema of well-known git keys.
+func displayConfigWarnings(errs gitconfig.ValidationErrors) {
+	label := promptui.Styler(promptui.BGYellow, promptui.FGBlack)("WARNING")
+	for _, err := range errs {
+		fmt.Printf("%s %s\n", label, promptui.Styler(promptui.FGYellow)(err))
+	}
+}
+
 func displayDeleteConfirmation() bool {
-	deletePrompt := promptui.Prompt{
-		Label:     "You're about to delete a GLOBAL config file, do you want to proceed",
+	return displayConfirmation("You're about to delete a GLOBAL config file, do you want to proceed")
+}
+
+// displayConfirmation asks a yes or no question, answering no by default.
+func displayConfirmation(label string) bool {
+	prompt := promptui.Prompt{
+		Label:     label,
 		IsConfirm: true,
 	}
 
-	_, err := deletePrompt.Run()
-	if err != nil {
-		return false
-	}
+	_, err := prompt.Run()
 	return err == nil
 }
 
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"bytes"
 	"crypto/md5"
 	"encoding/hex"
 	"errors"
@@ -10,6 +11,7 @@ import (
 	"runtime"
 
 	"github.com/manifoldco/promptui"
+	"github.com/thansetan/git-sw/pkg/gitconfig"
 )
 
 func hash(s string) (string, error) {
@@ -50,6 +52,88 @@ func openTextEditor(filePath string) error {
 	return cmd.Run()
 }
 
+// editConfig opens a copy of the config file at filePath in a text editor and
+// shows what was changed along with the problems of the edited config. The file
+// is only replaced by the copy if the user confirms it, it reports whether it was.
+func editConfig(filePath string) (bool, error) {
+	original, err := os.ReadFile(filePath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return false, err
+	}
+	// the file may not parse yet, which is what the user is about to fix
+	before, _ := gitconfig.ParseWith(original, gitconfig.ParseOptions{Lenient: true})
+
+	tmp, err := os.CreateTemp("", "git-sw-*.gitconfig")
+	if err != nil {
+		return false, err
+	}
+	defer os.Remove(tmp.Name())
+	_, err = tmp.Write(original)
+	if errClose := tmp.Close(); err == nil {
+		err = errClose
+	}
+	if err != nil {
+		return false, err
+	}
+
+	for {
+		err = openTextEditor(tmp.Name())
+		if err != nil {
+			return false, err
+		}
+		content, err := os.ReadFile(tmp.Name())
+		if err != nil {
+			return false, err
+		}
+		// report every problem of the edited file at once
+		after, err := gitconfig.ParseWith(content, gitconfig.ParseOptions{Lenient: true})
+		if err != nil {
+			fmt.Println(formatError(err))
+			if displayConfirmation("The edited config can't be parsed, do you want to edit it again") {
+				continue
+			}
+			fmt.Println("Changes discarded.")
+			return false, nil
+		}
+
+		displayConfigChanges(gitconfig.Diff(before, after))
+		if bytes.Equal(content, original) {
+			return false, nil
+		}
+		var warnings gitconfig.ValidationErrors
+		if errors.As(gitconfig.Validate(after), &warnings) {
+			displayConfigWarnings(warnings)
+		}
+		if !displayConfirmation("Do you want to save the changes") {
+			fmt.Println("Changes discarded.")
+			return false, nil
+		}
+		return true, after.Save(filePath)
+	}
+}
+
+// formatConfig formats the config file at path. With check, the file isn't
+// written and it reports whether the file is already formatted.
+func formatConfig(path string, opts gitconfig.FormatOptions, check bool) (bool, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return false, err
+	}
+	formatted, err := gitconfig.FormatSource(content, opts)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", path, err)
+	}
+	if check || bytes.Equal(content, formatted) {
+		return bytes.Equal(content, formatted), nil
+	}
+
+	gc, err := gitconfig.Parse(formatted)
+	if err != nil {
+		return false, err
+	}
+	return true, gc.Save(path)
+}
+
 func successMessage(profileName string, action Action) string {
 	label := promptui.Styler(promptui.BGGreen, promptui.FGWhite)("SUCCESS")
 	text := promptui.Styler(promptui.FGGreen)(fmt.Sprintf("%s profile \"%s\"", action, profileName))